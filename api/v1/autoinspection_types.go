@@ -37,11 +37,84 @@ type AutoInspectionSpec struct {
 	// 定义接收通知的邮件地址
 	NotifyTo []string `json:"notifyTo"`
 
-	// 定义Prometheus API地址
-	PrometheusURL string `json:"prometheusURL"`
+	// 定义Webhook通知渠道
+	// +optional
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+
+	// 通知策略：Always每次巡检都发送通知，OnAnomaly只在存在未被静默规则确认的异常时发送
+	// +kubebuilder:default=Always
+	// +optional
+	NotifyPolicy NotifyPolicy `json:"notifyPolicy,omitempty"`
+
+	// 定义Prometheus API地址，配置dataSources时可省略
+	// +optional
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// 多个Prometheus数据源，用于高可用部署和按地域分片的Prometheus，配置后忽略prometheusURL。
+	// 每台主机按顺序使用匹配的主数据源，查询结果为空时继续查询下一个主数据源，主数据源不可用时使用匹配的备用数据源
+	// +optional
+	DataSources []DataSource `json:"dataSources,omitempty"`
+
+	// Prometheus查询失败时的重试策略，只重试网络错误、429和5xx等临时错误。
+	// 未配置时最多请求3次，同一数据源地址和租户连续失败5次后熔断30秒
+	// +optional
+	QueryRetry *QueryRetry `json:"queryRetry,omitempty"`
 
 	// 定义巡检对象（业务的主机）
 	InspectionObject InspectionObject `json:"inspectionObject"`
+
+	// 定义报告输出配置
+	// +optional
+	Report Report `json:"report,omitempty"`
+
+	// 参与巡检的文件系统过滤条件和按挂载点的阈值
+	// +optional
+	Filesystems Filesystems `json:"filesystems,omitempty"`
+
+	// 对比偏移，第一个偏移的对比值和差值作为主对比列，其余偏移在结果表格末尾按偏移分组展示。
+	// 未配置时只与24h前对比
+	// +optional
+	Comparisons []Comparison `json:"comparisons,omitempty"`
+
+	// 按指标配置差值规则，未配置的指标只检查使用率上升的百分点是否超过对比偏移的差值阈值
+	// +optional
+	DeltaRules []DeltaRule `json:"deltaRules,omitempty"`
+
+	// 按主机、Prometheus目标标签或inspectionObject.groups中的分组覆盖阈值。
+	// 同一主机匹配多条规则时按主机、标签（标签越多越优先）、分组的顺序逐项使用最具体的值
+	// +optional
+	ThresholdOverrides []ThresholdOverride `json:"thresholdOverrides,omitempty"`
+
+	// 静默规则，匹配的异常在报告中标记为已确认，不计入异常主机，也不触发OnAnomaly通知。
+	// 过期的规则不再生效，并在报告中列出
+	// +optional
+	Silences []Silence `json:"silences,omitempty"`
+
+	// 最近一次抓取距今超过该时长的主机视为数据过期，默认3m
+	// +optional
+	StaleAfter *metav1.Duration `json:"staleAfter,omitempty"`
+
+	// 磁盘写满预测配置，为空时不做预测
+	// +optional
+	Forecast *Forecast `json:"forecast,omitempty"`
+
+	// 动态基线配置，为空时不计算基线。开启后每台主机需要额外执行4次范围查询
+	// +optional
+	Baseline *Baseline `json:"baseline,omitempty"`
+
+	// 导出到Operator指标端点的巡检结果，为空时按默认上限导出全部指标
+	// +optional
+	FindingMetrics *FindingMetrics `json:"findingMetrics,omitempty"`
+
+	// 报告、邮件主题和附件名使用的语言，默认中文
+	// +kubebuilder:validation:Enum=zh-CN;en-US
+	// +kubebuilder:default=zh-CN
+	// +optional
+	Language string `json:"language,omitempty"`
+
+	// 自定义报告模板，引用同命名空间下ConfigMap中的键，解析失败时使用内置模板
+	// +optional
+	ReportTemplateRef *ReportTemplateRef `json:"reportTemplateRef,omitempty"`
 }
 
 // Job定义巡检任务和调度
@@ -66,17 +139,399 @@ type SMTP struct {
 	Password string `json:"password"`
 }
 
+// Report定义报告输出配置
+type Report struct {
+	// 以附件形式发送的完整报告格式，配置后邮件正文只展示巡检摘要
+	// +optional
+	Attachments []ReportFormat `json:"attachments,omitempty"`
+
+	// 在HTML报告中嵌入最近24小时的趋势图和热力图，每台主机需要额外执行4次范围查询
+	// +optional
+	Charts bool `json:"charts,omitempty"`
+}
+
+// NotifyPolicy 通知策略
+// +kubebuilder:validation:Enum=Always;OnAnomaly
+type NotifyPolicy string
+
+const (
+	// NotifyPolicyAlways 每次巡检都发送通知
+	NotifyPolicyAlways NotifyPolicy = "Always"
+	// NotifyPolicyOnAnomaly 只在存在未确认的异常时发送通知
+	NotifyPolicyOnAnomaly NotifyPolicy = "OnAnomaly"
+)
+
+// ReportFormat 报告输出格式
+// +kubebuilder:validation:Enum=html;markdown;text;json;csv;xlsx
+type ReportFormat string
+
+const (
+	// ReportFormatHTML 完整HTML报告
+	ReportFormatHTML ReportFormat = "html"
+	// ReportFormatMarkdown 适合聊天机器人的精简Markdown报告
+	ReportFormatMarkdown ReportFormat = "markdown"
+	// ReportFormatText 纯文本报告
+	ReportFormatText ReportFormat = "text"
+	// ReportFormatJSON 带版本号的JSON报告，供下游自动化使用
+	ReportFormatJSON ReportFormat = "json"
+	// ReportFormatCSV 主机指标CSV导出
+	ReportFormatCSV ReportFormat = "csv"
+	// ReportFormatXLSX 带条件格式的Excel工作簿
+	ReportFormatXLSX ReportFormat = "xlsx"
+)
+
+// Webhook定义Webhook通知渠道，报告以所选格式POST到指定地址
+type Webhook struct {
+	// 渠道名称
+	Name string `json:"name"`
+	// 接收报告的地址
+	URL string `json:"url"`
+	// 报告格式
+	// +kubebuilder:default=json
+	// +optional
+	Format ReportFormat `json:"format,omitempty"`
+}
+
+// Filesystems 定义参与巡检的文件系统，过滤条件对所有硬盘和inode查询始终生效
+type Filesystems struct {
+	// 只巡检匹配的文件系统类型，正则表达式
+	// +optional
+	IncludeFSTypes []string `json:"includeFSTypes,omitempty"`
+
+	// 排除的文件系统类型，正则表达式，未配置时排除tmpfs和rootfs
+	// +optional
+	ExcludeFSTypes []string `json:"excludeFSTypes,omitempty"`
+
+	// 只巡检匹配的挂载点，正则表达式
+	// +optional
+	IncludeMountpoints []string `json:"includeMountpoints,omitempty"`
+
+	// 排除的挂载点，正则表达式
+	// +optional
+	ExcludeMountpoints []string `json:"excludeMountpoints,omitempty"`
+
+	// 按挂载点覆盖硬盘和inode使用率阈值，按顺序使用第一条匹配的规则
+	// +optional
+	Thresholds []MountpointThreshold `json:"thresholds,omitempty"`
+}
+
+// MountpointThreshold 定义挂载点的使用率阈值
+type MountpointThreshold struct {
+	// 挂载点正则表达式，需完整匹配，如/data.*
+	Mountpoint string `json:"mountpoint"`
+
+	// 硬盘使用率阈值（百分比），未配置时使用默认阈值80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Disk int32 `json:"disk,omitempty"`
+
+	// inode使用率阈值（百分比），未配置时使用默认阈值60
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Inode int32 `json:"inode,omitempty"`
+}
+
+// Comparison 定义对比偏移，当前值与偏移前的值之差超过阈值时标记为异常
+type Comparison struct {
+	// 相对巡检时间的偏移，Prometheus时长格式，如1d、7d、30d
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ms|s|m|h|d|w|y)$`
+	Offset string `json:"offset"`
+
+	// 差值阈值（百分点），未配置时使用默认阈值10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RateThreshold int32 `json:"rateThreshold,omitempty"`
+}
+
+// DeltaRule 定义单项指标的差值规则
+type DeltaRule struct {
+	// 指标名称
+	// +kubebuilder:validation:Enum=disk;inode;cpu;memory
+	Metric string `json:"metric"`
+
+	// 差值计算方式：absolute按百分点计算，relative按相对对比值的变化百分比计算
+	// +kubebuilder:validation:Enum=absolute;relative
+	// +kubebuilder:default=absolute
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// 检查的变化方向：up只检查上升，down只检查下降（如服务崩溃导致内存骤降），both两个方向都检查
+	// +kubebuilder:validation:Enum=up;down;both
+	// +kubebuilder:default=up
+	// +optional
+	Direction string `json:"direction,omitempty"`
+
+	// 差值阈值，absolute时单位为百分点，relative时单位为百分比；未配置时使用对比偏移的差值阈值
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+
+	// 对比值低于该使用率（百分比）时不检查差值，避免低使用率时的小幅波动被放大
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MinBaseline int32 `json:"minBaseline,omitempty"`
+}
+
+// ThresholdOverride 定义一条阈值覆盖规则，node、selector、group必须且只能指定一个
+type ThresholdOverride struct {
+	// 主机名称，与Prometheus中的instance一致
+	// +optional
+	Node string `json:"node,omitempty"`
+
+	// Prometheus目标标签，主机带有全部标签时匹配
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// inspectionObject.groups中的分组名称
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// 硬盘使用率阈值（百分比），未配置时不覆盖
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Disk int32 `json:"disk,omitempty"`
+
+	// inode使用率阈值（百分比），未配置时不覆盖
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Inode int32 `json:"inode,omitempty"`
+
+	// CPU使用率阈值（百分比），未配置时不覆盖
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	CPU int32 `json:"cpu,omitempty"`
+
+	// 内存使用率阈值（百分比），未配置时不覆盖
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Memory int32 `json:"memory,omitempty"`
+
+	// 按指标覆盖的差值规则
+	// +optional
+	DeltaRules []DeltaRule `json:"deltaRules,omitempty"`
+}
+
+// Silence 定义静默规则，node和selector至少指定一个，同时指定时主机需同时匹配
+type Silence struct {
+	// 主机名称正则表达式，需完整匹配，如 192\.168\.0\.5:9100
+	// +optional
+	Node string `json:"node,omitempty"`
+
+	// Prometheus目标标签，主机带有全部标签时匹配
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// 静默的指标，forecast为磁盘写满预测，reachability为主机不可达
+	// +kubebuilder:validation:Enum=disk;inode;cpu;memory;forecast;reachability
+	Metric string `json:"metric"`
+
+	// 静默原因
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+
+	// 负责人
+	// +kubebuilder:validation:MinLength=1
+	Owner string `json:"owner"`
+
+	// 过期时间，过期后不再生效
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// Forecast 定义磁盘写满预测，按窗口内可用空间的线性回归推算写满时间
+type Forecast struct {
+	// 线性回归使用的时间窗口，Prometheus时长格式，如6h、1d
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ms|s|m|h|d|w|y)$`
+	// +kubebuilder:default="24h"
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// 预计写满天数低于该值时标记为异常
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=7
+	// +optional
+	ThresholdDays int32 `json:"thresholdDays,omitempty"`
+}
+
+// Baseline 定义动态基线，按最近days天的历史数据计算各项指标的均值和标准差，
+// 当前值偏离均值超过sigma倍标准差时标记为异常，与静态阈值同时生效
+type Baseline struct {
+	// 计算基线使用的历史天数
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=90
+	// +kubebuilder:default=7
+	// +optional
+	Days int32 `json:"days,omitempty"`
+
+	// 偏离均值超过该倍数的标准差时标记为异常
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=3
+	// +optional
+	Sigma int32 `json:"sigma,omitempty"`
+
+	// 范围查询的采样间隔，Prometheus时长格式
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ms|s|m|h|d|w|y)$`
+	// +kubebuilder:default="1h"
+	// +optional
+	Step string `json:"step,omitempty"`
+}
+
+// QueryRetry 定义Prometheus查询的重试策略，重试间隔按指数增长并加入随机抖动，
+// 响应中带有Retry-After时按其等待
+type QueryRetry struct {
+	// 每次查询最多请求的次数，包括第一次请求，为1时不重试
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=3
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// 第一次重试前的等待时间，之后每次翻倍
+	// +kubebuilder:default="500ms"
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+
+	// 两次请求之间最长的等待时间，Retry-After超过该值时不再重试
+	// +kubebuilder:default="10s"
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// DataSourceRole 数据源角色
+// +kubebuilder:validation:Enum=Primary;Fallback
+type DataSourceRole string
+
+const (
+	// DataSourceRolePrimary 主数据源
+	DataSourceRolePrimary DataSourceRole = "Primary"
+	// DataSourceRoleFallback 备用数据源，只在主数据源不可用时查询
+	DataSourceRoleFallback DataSourceRole = "Fallback"
+)
+
+// DataSourceType 数据源的存储后端类型
+// +kubebuilder:validation:Enum=Prometheus;VictoriaMetrics;Thanos;Mimir
+type DataSourceType string
+
+const (
+	DataSourceTypePrometheus      DataSourceType = "Prometheus"
+	DataSourceTypeVictoriaMetrics DataSourceType = "VictoriaMetrics"
+	DataSourceTypeThanos          DataSourceType = "Thanos"
+	DataSourceTypeMimir           DataSourceType = "Mimir"
+)
+
+// DataSource 定义一个Prometheus数据源
+type DataSource struct {
+	// 数据源名称，在报告中标注每台主机的数据来源
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Prometheus API地址
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// 数据源角色
+	// +kubebuilder:default=Primary
+	// +optional
+	Role DataSourceRole `json:"role,omitempty"`
+
+	// 主机名称正则表达式，只有匹配的主机使用该数据源，为空时匹配所有主机。
+	// 通过labels查询主机时，每台主机只使用返回了该主机的数据源
+	// +optional
+	Nodes string `json:"nodes,omitempty"`
+
+	// 存储后端类型，决定请求的路径、参数和请求头
+	// +kubebuilder:default=Prometheus
+	// +optional
+	Type DataSourceType `json:"type,omitempty"`
+
+	// API路径前缀，如VictoriaMetrics集群版的 /select/0/prometheus。
+	// 为空时Mimir使用 /prometheus，VictoriaMetrics配置了tenant时使用 /select/<tenant>/prometheus
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+
+	// 租户，Mimir通过X-Scope-OrgID请求头传递，Thanos通过THANOS-TENANT请求头传递，
+	// VictoriaMetrics集群版在未配置pathPrefix时放在路径中，如 0 或 0:1
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// Thanos是否对高可用副本的数据去重，默认去重
+	// +optional
+	Dedup *bool `json:"dedup,omitempty"`
+
+	// 部分存储节点不可用时是否接受不完整的结果，未配置时使用后端的默认行为。
+	// Thanos对应partial_response参数，VictoriaMetrics为false时设置deny_partial_response
+	// +optional
+	PartialResponse *bool `json:"partialResponse,omitempty"`
+}
+
+// FindingMetrics 定义导出的巡检结果指标，每台主机每项指标最多导出4条时间序列，
+// 主机数较多时可通过metrics、abnormalOnly和maxNodes控制时间序列数量
+type FindingMetrics struct {
+	// 不导出巡检结果指标，已导出的时间序列会在下次巡检后移除
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// 导出的指标，为空时导出全部
+	// +kubebuilder:validation:items:Enum=disk;inode;cpu;memory;forecast;reachability
+	// +optional
+	Metrics []string `json:"metrics,omitempty"`
+
+	// 只导出存在异常或已确认异常的主机
+	// +optional
+	AbnormalOnly bool `json:"abnormalOnly,omitempty"`
+
+	// 导出的主机数上限，超过时优先导出严重程度高的主机
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10000
+	// +kubebuilder:default=200
+	// +optional
+	MaxNodes int32 `json:"maxNodes,omitempty"`
+}
+
+// ReportTemplateRef 引用ConfigMap中的报告模板
+type ReportTemplateRef struct {
+	// ConfigMap名称
+	Name string `json:"name"`
+	// 模板所在的键
+	Key string `json:"key"`
+}
+
 // 巡检对象
 type InspectionObject struct {
 	// 业务名称
 	Business string `json:"business"`
 	// 主机名称
 	Hosts Hosts `json:"hosts"`
+	// 主机分组，用于按分组覆盖阈值，不会增加参与巡检的主机
+	// +optional
+	Groups []NodeGroup `json:"groups,omitempty"`
+}
+
+// NodeGroup 主机分组，主机在nodes中或带有labels中的全部Prometheus目标标签时属于该分组
+type NodeGroup struct {
+	// 分组名称
+	Name string `json:"name"`
+	// 主机列表
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+	// 标签
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // 主机信息
 type Hosts struct {
-	// 标签
+	// 标签，按Prometheus目标标签选择主机。值以!=、=~、!~开头时按不等、正则匹配、正则不匹配处理，
+	// 如 env: "!=prod"、role: "=~web|api"，否则按相等匹配。
+	// 通过标签查询主机时至少需要一个不匹配空值的条件，如相等匹配
 	Labels map[string]string `json:"labels,omitempty"`
 	// 主机列表
 	Nodes []string `json:"nodes,omitempty"`
@@ -89,8 +544,20 @@ type AutoInspectionStatus struct {
 
 	// 最后巡检时间
 	LastInspectionTime *metav1.Time `json:"lastInspectionTime,omitempty"`
+
+	// 当前状态，如自定义报告模板是否可用
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// 状态条件类型
+const (
+	// ConditionReportTemplateReady 自定义报告模板已成功加载
+	ConditionReportTemplateReady = "ReportTemplateReady"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
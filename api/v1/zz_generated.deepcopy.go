@@ -21,6 +21,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -97,7 +98,75 @@ func (in *AutoInspectionSpec) DeepCopyInto(out *AutoInspectionSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]Webhook, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataSources != nil {
+		in, out := &in.DataSources, &out.DataSources
+		*out = make([]DataSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueryRetry != nil {
+		in, out := &in.QueryRetry, &out.QueryRetry
+		*out = new(QueryRetry)
+		(*in).DeepCopyInto(*out)
+	}
 	in.InspectionObject.DeepCopyInto(&out.InspectionObject)
+	in.Report.DeepCopyInto(&out.Report)
+	in.Filesystems.DeepCopyInto(&out.Filesystems)
+	if in.Comparisons != nil {
+		in, out := &in.Comparisons, &out.Comparisons
+		*out = make([]Comparison, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeltaRules != nil {
+		in, out := &in.DeltaRules, &out.DeltaRules
+		*out = make([]DeltaRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ThresholdOverrides != nil {
+		in, out := &in.ThresholdOverrides, &out.ThresholdOverrides
+		*out = make([]ThresholdOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Silences != nil {
+		in, out := &in.Silences, &out.Silences
+		*out = make([]Silence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaleAfter != nil {
+		in, out := &in.StaleAfter, &out.StaleAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Forecast != nil {
+		in, out := &in.Forecast, &out.Forecast
+		*out = new(Forecast)
+		**out = **in
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(Baseline)
+		**out = **in
+	}
+	if in.FindingMetrics != nil {
+		in, out := &in.FindingMetrics, &out.FindingMetrics
+		*out = new(FindingMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportTemplateRef != nil {
+		in, out := &in.ReportTemplateRef, &out.ReportTemplateRef
+		*out = new(ReportTemplateRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoInspectionSpec.
@@ -117,6 +186,13 @@ func (in *AutoInspectionStatus) DeepCopyInto(out *AutoInspectionStatus) {
 		in, out := &in.LastInspectionTime, &out.LastInspectionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoInspectionStatus.
@@ -129,6 +205,151 @@ func (in *AutoInspectionStatus) DeepCopy() *AutoInspectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Baseline) DeepCopyInto(out *Baseline) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Baseline.
+func (in *Baseline) DeepCopy() *Baseline {
+	if in == nil {
+		return nil
+	}
+	out := new(Baseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Comparison) DeepCopyInto(out *Comparison) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Comparison.
+func (in *Comparison) DeepCopy() *Comparison {
+	if in == nil {
+		return nil
+	}
+	out := new(Comparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSource) DeepCopyInto(out *DataSource) {
+	*out = *in
+	if in.Dedup != nil {
+		in, out := &in.Dedup, &out.Dedup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PartialResponse != nil {
+		in, out := &in.PartialResponse, &out.PartialResponse
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
+func (in *DataSource) DeepCopy() *DataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeltaRule) DeepCopyInto(out *DeltaRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeltaRule.
+func (in *DeltaRule) DeepCopy() *DeltaRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeltaRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Filesystems) DeepCopyInto(out *Filesystems) {
+	*out = *in
+	if in.IncludeFSTypes != nil {
+		in, out := &in.IncludeFSTypes, &out.IncludeFSTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeFSTypes != nil {
+		in, out := &in.ExcludeFSTypes, &out.ExcludeFSTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeMountpoints != nil {
+		in, out := &in.IncludeMountpoints, &out.IncludeMountpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeMountpoints != nil {
+		in, out := &in.ExcludeMountpoints, &out.ExcludeMountpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make([]MountpointThreshold, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filesystems.
+func (in *Filesystems) DeepCopy() *Filesystems {
+	if in == nil {
+		return nil
+	}
+	out := new(Filesystems)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FindingMetrics) DeepCopyInto(out *FindingMetrics) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FindingMetrics.
+func (in *FindingMetrics) DeepCopy() *FindingMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(FindingMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Forecast) DeepCopyInto(out *Forecast) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Forecast.
+func (in *Forecast) DeepCopy() *Forecast {
+	if in == nil {
+		return nil
+	}
+	out := new(Forecast)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hosts) DeepCopyInto(out *Hosts) {
 	*out = *in
@@ -160,6 +381,13 @@ func (in *Hosts) DeepCopy() *Hosts {
 func (in *InspectionObject) DeepCopyInto(out *InspectionObject) {
 	*out = *in
 	in.Hosts.DeepCopyInto(&out.Hosts)
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]NodeGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InspectionObject.
@@ -187,6 +415,108 @@ func (in *Job) DeepCopy() *Job {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountpointThreshold) DeepCopyInto(out *MountpointThreshold) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountpointThreshold.
+func (in *MountpointThreshold) DeepCopy() *MountpointThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(MountpointThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroup.
+func (in *NodeGroup) DeepCopy() *NodeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryRetry) DeepCopyInto(out *QueryRetry) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRetry.
+func (in *QueryRetry) DeepCopy() *QueryRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Report) DeepCopyInto(out *Report) {
+	*out = *in
+	if in.Attachments != nil {
+		in, out := &in.Attachments, &out.Attachments
+		*out = make([]ReportFormat, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Report.
+func (in *Report) DeepCopy() *Report {
+	if in == nil {
+		return nil
+	}
+	out := new(Report)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportTemplateRef) DeepCopyInto(out *ReportTemplateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportTemplateRef.
+func (in *ReportTemplateRef) DeepCopy() *ReportTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SMTP) DeepCopyInto(out *SMTP) {
 	*out = *in
@@ -201,3 +531,68 @@ func (in *SMTP) DeepCopy() *SMTP {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Silence) DeepCopyInto(out *Silence) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Silence.
+func (in *Silence) DeepCopy() *Silence {
+	if in == nil {
+		return nil
+	}
+	out := new(Silence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThresholdOverride) DeepCopyInto(out *ThresholdOverride) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DeltaRules != nil {
+		in, out := &in.DeltaRules, &out.DeltaRules
+		*out = make([]DeltaRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThresholdOverride.
+func (in *ThresholdOverride) DeepCopy() *ThresholdOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ThresholdOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Webhook) DeepCopyInto(out *Webhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Webhook.
+func (in *Webhook) DeepCopy() *Webhook {
+	if in == nil {
+		return nil
+	}
+	out := new(Webhook)
+	in.DeepCopyInto(out)
+	return out
+}
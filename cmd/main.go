@@ -17,10 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -39,6 +41,7 @@ import (
 
 	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
 	"github.com/rxg456/auto-inspection-operator/internal/controller"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -64,6 +67,7 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
+	var tracingConfig tracing.Config
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -81,6 +85,14 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&tracingConfig.Endpoint, "tracing-endpoint", "",
+		"The OTLP gRPC endpoint (host:port) to export inspection traces to. Tracing is disabled when empty.")
+	flag.BoolVar(&tracingConfig.Insecure, "tracing-insecure", false,
+		"If set, traces are exported to the OTLP endpoint without TLS.")
+	flag.Float64Var(&tracingConfig.SampleRatio, "tracing-sample-ratio", 1,
+		"The fraction of reconciles to trace, between 0 and 1.")
+	flag.StringVar(&tracingConfig.ServiceName, "tracing-service-name", "auto-inspection-operator",
+		"The service name reported with exported traces.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -89,6 +101,16 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if tracingConfig.Endpoint != "" {
+		setupLog.Info("exporting inspection traces", "endpoint", tracingConfig.Endpoint,
+			"sampleRatio", tracingConfig.SampleRatio)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -203,8 +225,9 @@ func main() {
 	}
 
 	if err = (&controller.AutoInspectionReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("autoinspection-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AutoInspection")
 		os.Exit(1)
@@ -241,4 +264,11 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// flush the remaining spans before exiting
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		setupLog.Error(err, "problem shutting down tracing")
+	}
 }
@@ -18,89 +18,158 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"html/template"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
 	"github.com/rxg456/auto-inspection-operator/internal/controller/inspection"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/metrics"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/report"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/tracing"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // AutoInspectionReconciler reconciles a AutoInspection object
 type AutoInspectionReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// 已解析的自定义报告模板缓存
+	templateCache *report.TemplateCache
 }
 
 // +kubebuilder:rbac:groups=devops.rxg98.cn,resources=autoinspections,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=devops.rxg98.cn,resources=autoinspections/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=devops.rxg98.cn,resources=autoinspections/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *AutoInspectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "Reconcile",
+		tracing.AttrNamespace.String(req.Namespace), tracing.AttrName.String(req.Name))
+	result, err := r.reconcile(ctx, req)
+	tracing.End(span, err)
+	return result, err
+}
+
+// reconcile 执行到期的巡检任务并计算下一次检查时间
+func (r *AutoInspectionReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Reconciling AutoInspection", "namespacedName", req.NamespacedName)
 
 	// 获取AutoInspection对象
 	var app devopsv1.AutoInspection
 	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
-		// 如果找不到资源，可能已被删除
+		// 如果找不到资源，可能已被删除，同时清理其自监控指标和模板缓存
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteInspection(req.Namespace, req.Name)
+			r.templateCache.Delete(req.NamespacedName.String())
+			return ctrl.Result{}, nil
+		}
 		logger.Error(err, "Unable to fetch AutoInspection")
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return ctrl.Result{}, err
 	}
 
-	// 检查每个任务是否应该执行
+	// 检查每个任务是否应该执行，多个任务同时到期时只执行第一个
 	shouldRun := false
 	var jobToRun *devopsv1.Job
 	for i, job := range app.Spec.Jobs {
 		canRun, err := inspection.ShouldRunNow(job, app.Status.LastInspectionTime)
 		if err != nil {
 			logger.Error(err, "检查任务执行时间失败", "job", job.Name)
+			r.event(&app, corev1.EventTypeWarning, inspection.EventInvalidSchedule,
+				"巡检任务%s的调度时间%q无效: %v", job.Name, job.Schedule, err)
 			continue
 		}
 
-		if canRun {
-			shouldRun = true
-			jobToRun = &app.Spec.Jobs[i]
-			break
+		if !canRun {
+			continue
 		}
+		if jobToRun != nil {
+			// 各任务共用上次巡检时间，本次执行后其余到期的任务不再执行
+			r.event(&app, corev1.EventTypeNormal, inspection.EventRunSkipped,
+				"巡检任务%s与%s同时到期，本次只执行%s", job.Name, jobToRun.Name, jobToRun.Name)
+			continue
+		}
+		shouldRun = true
+		jobToRun = &app.Spec.Jobs[i]
 	}
 
 	// 如果没有任务需要执行，则计算下一次检查时间
 	if !shouldRun || jobToRun == nil {
+		recordNextRuns(ctx, &app)
 		// 默认10分钟后重新检查
 		return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
 	}
 
 	logger.Info("开始执行巡检任务", "job", jobToRun.Name)
+	trace.SpanFromContext(ctx).SetAttributes(tracing.AttrJob.String(jobToRun.Name))
+	r.event(&app, corev1.EventTypeNormal, inspection.EventRunStarted, "开始执行巡检任务%s", jobToRun.Name)
+
+	// 加载自定义报告模板，失败时回退到内置模板
+	opts := []inspection.Option{
+		inspection.WithHistory(newConfigMapHistory(r.Client, r.Scheme, &app, jobToRun.Name)),
+		inspection.WithEventRecorder(r.Recorder),
+	}
+	tmpl, changed := r.loadReportTemplate(ctx, &app)
+	if tmpl != nil {
+		opts = append(opts, inspection.WithReportTemplate(tmpl))
+	}
+	// 模板条件在巡检前写入状态，巡检失败时用户也能看到回退到内置模板的原因。
+	// 写入失败（如对象已被修改）时不执行巡检，否则巡检结果同样无法记录，下次调谐会重复发送报告
+	if changed {
+		if err := r.Status().Update(ctx, &app); err != nil {
+			logger.Error(err, "更新ReportTemplateReady条件失败")
+			return ctrl.Result{}, err
+		}
+	}
 
-	inspector, err := inspection.NewInspector(&app)
+	start := time.Now()
+	inspector, err := inspection.NewInspector(&app, opts...)
 	if err != nil {
 		logger.Error(err, "创建巡检器失败")
+		r.event(&app, corev1.EventTypeWarning, inspection.EventRunFailed, "创建巡检器失败: %v", err)
+		metrics.ObserveRun(app.Namespace, app.Name, jobToRun.Name, start, err)
 		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 	}
 
 	// 执行巡检
-	if err := inspector.RunInspection(ctx); err != nil {
+	err = inspector.RunInspection(ctx)
+	metrics.ObserveRun(app.Namespace, app.Name, jobToRun.Name, start, err)
+	if err != nil {
 		logger.Error(err, "执行巡检失败")
+		r.event(&app, corev1.EventTypeWarning, inspection.EventRunFailed, "巡检任务%s执行失败: %v", jobToRun.Name, err)
 		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 	}
 
-	// 更新状态
+	// 更新状态，巡检期间对象可能已被修改，使用合并补丁只写入上次巡检时间，避免因版本冲突丢失记录而重复巡检
+	patch := client.MergeFrom(app.DeepCopy())
 	now := metav1.NewTime(time.Now())
 	app.Status.LastInspectionTime = &now
 
-	if err := r.Status().Update(ctx, &app); err != nil {
+	if err := r.Status().Patch(ctx, &app, patch); err != nil {
 		logger.Error(err, "更新AutoInspection状态失败")
 		return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
 	}
 
 	// 计算下一次运行时间
+	recordNextRuns(ctx, &app)
 	nextRun, err := inspection.GetNextRunTime(*jobToRun, app.Status.LastInspectionTime)
 	if err != nil {
 		logger.Error(err, "计算下一次运行时间失败")
@@ -117,8 +186,84 @@ func (r *AutoInspectionReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ctrl.Result{RequeueAfter: waitDuration}, nil
 }
 
+// recordNextRuns 更新各巡检任务下一次计划执行时间的指标，已从spec中移除的任务不再保留
+func recordNextRuns(ctx context.Context, app *devopsv1.AutoInspection) {
+	metrics.NextRunTimestamp.DeletePartialMatch(prometheus.Labels{"namespace": app.Namespace, "name": app.Name})
+	for _, job := range app.Spec.Jobs {
+		nextRun, err := inspection.GetNextRunTime(job, app.Status.LastInspectionTime)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "计算下一次运行时间失败", "job", job.Name)
+			continue
+		}
+		metrics.SetNextRun(app.Namespace, app.Name, job.Name, nextRun)
+	}
+}
+
+// loadReportTemplate 加载spec.reportTemplateRef引用的自定义报告模板，并记录ReportTemplateReady条件，
+// 同时返回条件是否发生变化。未配置或加载失败时返回nil，由巡检器使用内置模板。
+func (r *AutoInspectionReconciler) loadReportTemplate(ctx context.Context, app *devopsv1.AutoInspection) (*template.Template, bool) {
+	logger := log.FromContext(ctx)
+
+	ref := app.Spec.ReportTemplateRef
+	if ref == nil {
+		r.templateCache.Delete(client.ObjectKeyFromObject(app).String())
+		return nil, meta.RemoveStatusCondition(&app.Status.Conditions, devopsv1.ConditionReportTemplateReady)
+	}
+
+	condition := metav1.Condition{
+		Type:               devopsv1.ConditionReportTemplateReady,
+		ObservedGeneration: app.Generation,
+	}
+
+	tmpl, err := r.parseReportTemplate(ctx, app, ref)
+	if err != nil {
+		logger.Error(err, "加载自定义报告模板失败，使用内置模板", "configMap", ref.Name, "key", ref.Key)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "TemplateInvalid"
+		condition.Message = fmt.Sprintf("自定义报告模板不可用，已使用内置模板: %v", err)
+	} else {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "TemplateLoaded"
+		condition.Message = fmt.Sprintf("已加载ConfigMap %s中的报告模板%s", ref.Name, ref.Key)
+	}
+	changed := meta.SetStatusCondition(&app.Status.Conditions, condition)
+
+	return tmpl, changed
+}
+
+// parseReportTemplate 读取ConfigMap中的模板并解析，按resourceVersion复用已解析的模板，
+// 缓存以AutoInspection为key，每个对象只保留当前引用的模板
+func (r *AutoInspectionReconciler) parseReportTemplate(
+	ctx context.Context,
+	app *devopsv1.AutoInspection,
+	ref *devopsv1.ReportTemplateRef,
+) (*template.Template, error) {
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: ref.Name}, &cm); err != nil {
+		return nil, fmt.Errorf("获取ConfigMap %s失败: %w", ref.Name, err)
+	}
+
+	text, ok := cm.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s中不存在键%s", ref.Name, ref.Key)
+	}
+
+	source := fmt.Sprintf("%s/%s/%s", app.Namespace, ref.Name, ref.Key)
+	return r.templateCache.Get(client.ObjectKeyFromObject(app).String(), source, cm.ResourceVersion, text)
+}
+
+// event 记录AutoInspection的事件，未配置Recorder时忽略
+func (r *AutoInspectionReconciler) event(app *devopsv1.AutoInspection, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(app, eventType, reason, messageFmt, args...)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AutoInspectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.templateCache = report.NewTemplateCache()
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&devopsv1.AutoInspection{}).
 		Named("autoinspection").
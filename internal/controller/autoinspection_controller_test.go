@@ -22,7 +22,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +33,23 @@ import (
 	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
 )
 
+// conflictingStatusClient 更新状态时总是返回版本冲突，模拟对象在调谐期间被修改
+type conflictingStatusClient struct {
+	client.Client
+}
+
+func (c conflictingStatusClient) Status() client.SubResourceWriter {
+	return conflictingStatusWriter{SubResourceWriter: c.Client.Status()}
+}
+
+type conflictingStatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (w conflictingStatusWriter) Update(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	return errors.NewConflict(devopsv1.GroupVersion.WithResource("autoinspections").GroupResource(), obj.GetName(), nil)
+}
+
 var _ = Describe("AutoInspection Controller", func() {
 	Context("When reconciling a resource", func() {
 		const resourceName = "test-resource"
@@ -81,4 +101,133 @@ var _ = Describe("AutoInspection Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When a job has an invalid schedule", func() {
+		const resourceName = "invalid-schedule"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &devopsv1.AutoInspection{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: devopsv1.AutoInspectionSpec{
+					Jobs:          []devopsv1.Job{{Name: "daily", Schedule: "not a cron"}},
+					SMTP:          devopsv1.SMTP{Server: "smtp.example.com", Port: 25, From: "ops@example.com"},
+					NotifyTo:      []string{"ops@example.com"},
+					PrometheusURL: "http://prometheus:9090",
+					InspectionObject: devopsv1.InspectionObject{
+						Business: "devops",
+						Hosts:    devopsv1.Hosts{Nodes: []string{"192.168.0.1:9100"}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &devopsv1.AutoInspection{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should record a warning event", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &AutoInspectionReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning InvalidSchedule 巡检任务daily的调度时间")))
+		})
+	})
+
+	Context("When the report template cannot be loaded", func() {
+		const resourceName = "missing-template"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			resource := &devopsv1.AutoInspection{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: devopsv1.AutoInspectionSpec{
+					Jobs:          []devopsv1.Job{{Name: "daily", Schedule: "0 10 * * *"}},
+					SMTP:          devopsv1.SMTP{Server: "smtp.example.com", Port: 25, From: "ops@example.com"},
+					NotifyTo:      []string{"ops@example.com"},
+					PrometheusURL: "http://prometheus:9090",
+					InspectionObject: devopsv1.InspectionObject{
+						Business: "devops",
+						// 无效的标签名称使巡检在查询Prometheus之前失败
+						Hosts: devopsv1.Hosts{Labels: map[string]string{"invalid-label": "devops"}},
+					},
+					ReportTemplateRef: &devopsv1.ReportTemplateRef{Name: "missing-template", Key: "report.html"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &devopsv1.AutoInspection{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should persist the fallback condition even when the inspection fails", func() {
+			controllerReconciler := &AutoInspectionReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &devopsv1.AutoInspection{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.LastInspectionTime).To(BeNil())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, devopsv1.ConditionReportTemplateReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("TemplateInvalid"))
+			Expect(condition.Message).To(ContainSubstring("missing-template"))
+		})
+
+		It("should not run the inspection when the condition cannot be persisted", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &AutoInspectionReconciler{
+				Client:   conflictingStatusClient{Client: k8sClient},
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(errors.IsConflict(err)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal RunStarted")))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
 })
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/report"
+)
+
+// historyConfigMapSuffix 保存巡检历史的ConfigMap名称后缀
+const historyConfigMapSuffix = "-history"
+
+// invalidHistoryKey ConfigMap键中不允许出现的字符
+var invalidHistoryKey = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// configMapHistory 将巡检任务上次的结果保存在ConfigMap中，每个任务一个键。
+// ConfigMap属于对应的AutoInspection，随其一起删除。
+type configMapHistory struct {
+	client client.Client
+	scheme *runtime.Scheme
+	owner  *devopsv1.AutoInspection
+	key    string
+}
+
+// newConfigMapHistory 创建巡检任务的历史存储
+func newConfigMapHistory(c client.Client, scheme *runtime.Scheme, owner *devopsv1.AutoInspection, job string) *configMapHistory {
+	return &configMapHistory{
+		client: c,
+		scheme: scheme,
+		owner:  owner,
+		key:    invalidHistoryKey.ReplaceAllString(job, "_") + ".json",
+	}
+}
+
+// name 返回ConfigMap名称
+func (h *configMapHistory) name() types.NamespacedName {
+	return types.NamespacedName{Namespace: h.owner.Namespace, Name: h.owner.Name + historyConfigMapSuffix}
+}
+
+// Load 读取任务上次的巡检结果，没有记录时返回nil
+func (h *configMapHistory) Load(ctx context.Context) (*report.Snapshot, error) {
+	var cm corev1.ConfigMap
+	if err := h.client.Get(ctx, h.name(), &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("获取巡检历史ConfigMap失败: %w", err)
+	}
+
+	data, ok := cm.Data[h.key]
+	if !ok {
+		return nil, nil
+	}
+
+	var snapshot report.Snapshot
+	if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+		return nil, fmt.Errorf("解析巡检历史%s失败: %w", h.key, err)
+	}
+	return &snapshot, nil
+}
+
+// Save 保存本次巡检结果，覆盖该任务上次的记录
+func (h *configMapHistory) Save(ctx context.Context, snapshot *report.Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("序列化巡检历史失败: %w", err)
+	}
+
+	name := h.name()
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: name.Name}}
+	_, err = controllerutil.CreateOrUpdate(ctx, h.client, cm, func() error {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[h.key] = string(data)
+		return controllerutil.SetOwnerReference(h.owner, cm, h.scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("保存巡检历史ConfigMap失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/report"
+)
+
+var _ = Describe("ConfigMap history", func() {
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+		owner  *devopsv1.AutoInspection
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(devopsv1.AddToScheme(scheme)).To(Succeed())
+		owner = &devopsv1.AutoInspection{ObjectMeta: metav1.ObjectMeta{
+			Name: "inspection", Namespace: "default", UID: "c1b2f3d4",
+		}}
+	})
+
+	It("should return nil when the ConfigMap does not exist", func() {
+		history := newConfigMapHistory(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme, owner, "daily")
+		snapshot, err := history.Load(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot).To(BeNil())
+	})
+
+	It("should save and load snapshots per job", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		since := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+		saved := &report.Snapshot{
+			GeneratedAt: since,
+			Nodes: map[string]report.NodeSnapshot{
+				"192.168.0.1:9100": {Status: 1, Findings: map[string]time.Time{report.MetricDisk: since}},
+			},
+		}
+
+		daily := newConfigMapHistory(c, scheme, owner, "daily check")
+		Expect(daily.Save(ctx, saved)).To(Succeed())
+
+		loaded, err := daily.Load(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.GeneratedAt).To(BeTemporally("==", since))
+		Expect(loaded.Nodes).To(HaveKey("192.168.0.1:9100"))
+		Expect(loaded.Nodes["192.168.0.1:9100"].Findings[report.MetricDisk]).To(BeTemporally("==", since))
+
+		// 其他任务使用不同的键，没有记录时返回nil
+		weekly, err := newConfigMapHistory(c, scheme, owner, "weekly").Load(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(weekly).To(BeNil())
+
+		var cm corev1.ConfigMap
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "inspection-history"}, &cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKey("daily_check.json"))
+	})
+
+	It("should set the AutoInspection as the owner of the ConfigMap", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		Expect(newConfigMapHistory(c, scheme, owner, "daily").Save(ctx, &report.Snapshot{})).To(Succeed())
+
+		var cm corev1.ConfigMap
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "inspection-history"}, &cm)).To(Succeed())
+		Expect(cm.OwnerReferences).To(HaveLen(1))
+		Expect(cm.OwnerReferences[0].Kind).To(Equal("AutoInspection"))
+		Expect(cm.OwnerReferences[0].Name).To(Equal("inspection"))
+		Expect(cm.OwnerReferences[0].UID).To(Equal(owner.UID))
+	})
+
+	It("should reject corrupted history", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "inspection-history"},
+			Data:       map[string]string{"daily.json": "{"},
+		}).Build()
+		_, err := newConfigMapHistory(c, scheme, owner, "daily").Load(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"fmt"
+	"time"
+)
+
+// Language 报告语言
+type Language string
+
+// 支持的报告语言
+const (
+	Chinese Language = "zh-CN"
+	English Language = "en-US"
+)
+
+// DefaultLanguage 未配置语言时使用的默认语言
+const DefaultLanguage = Chinese
+
+// Catalog 某种语言的消息目录
+type Catalog struct {
+	// 目录对应的语言
+	Language Language
+
+	messages        map[string]string
+	dateLayout      string
+	timestampLayout string
+}
+
+// catalogs 已支持语言的消息目录
+var catalogs = map[Language]*Catalog{
+	Chinese: {
+		Language:        Chinese,
+		messages:        zhCN,
+		dateLayout:      "2006-01-02",
+		timestampLayout: "2006-01-02 15:04:05",
+	},
+	English: {
+		Language:        English,
+		messages:        enUS,
+		dateLayout:      "Jan 2, 2006",
+		timestampLayout: "Jan 2, 2006 15:04:05 MST",
+	},
+}
+
+// NewCatalog 返回指定语言的消息目录，不支持的语言回退到默认语言
+func NewCatalog(language string) *Catalog {
+	if catalog, ok := catalogs[Language(language)]; ok {
+		return catalog
+	}
+	return catalogs[DefaultLanguage]
+}
+
+// T 返回key对应的消息，有参数时按fmt.Sprintf格式化。
+// 当前语言缺少该消息时回退到默认语言，仍不存在时返回key本身。
+func (c *Catalog) T(key string, args ...interface{}) string {
+	message, ok := c.messages[key]
+	if !ok {
+		if message, ok = catalogs[DefaultLanguage].messages[key]; !ok {
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// FormatDate 按语言习惯格式化日期
+func (c *Catalog) FormatDate(t time.Time) string {
+	return t.Format(c.dateLayout)
+}
+
+// FormatTimestamp 按语言习惯格式化时间
+func (c *Catalog) FormatTimestamp(t time.Time) string {
+	return t.Format(c.timestampLayout)
+}
@@ -0,0 +1,391 @@
+package i18n
+
+// zhCN 中文消息目录
+var zhCN = map[string]string{
+	// 报告标题与页脚
+	"report.title":       "%s业务系统巡检报告",
+	"report.generatedAt": "生成时间: %s",
+	"report.footer":      "此报告由自动巡检系统生成 © %s",
+
+	// 巡检说明
+	"section.description":          "巡检说明",
+	"desc.values.title":            "巡检报表取值说明",
+	"desc.values.body":             "以巡检时间为参考，巡检报表中的各项指标（硬盘使用率、inode使用率、CPU使用率、内存使用率）的取值规则如下：",
+	"desc.selection.title":         "节点选择机制",
+	"desc.selection.modes.label":   "两种方式:",
+	"desc.selection.modes.body":    "系统支持通过明确的节点列表或标签自动发现进行巡检",
+	"desc.selection.nodes.label":   "节点列表:",
+	"desc.selection.nodes.body":    "直接指定IP地址列表，如",
+	"desc.selection.labels.label":  "标签自动发现:",
+	"desc.selection.labels.body":   "通过标签自动查找匹配的节点，如",
+	"desc.selection.labels.suffix": "，系统将查询所有具有这些标签的节点",
+	"desc.selection.mixed.label":   "混合模式:",
+	"desc.selection.mixed.body":    "同时支持指定节点列表和标签，将对两者找到的所有节点进行巡检",
+	"desc.label.definition":        "定义:",
+	"desc.label.source":            "来源:",
+	"desc.label.calculation":       "指标计算:",
+	"desc.label.metric":            "指标:",
+	"desc.label.formula":           "公式:",
+	"desc.label.meaning":           "含义:",
+	"desc.current.title":           "1. 当前值（最近5分钟内的最新值）",
+	"desc.current.definition":      "当前值表示系统在最近 5 分钟内的最新状态。",
+	"desc.current.source":          "从 Prometheus 监控系统获取的实时查询结果。",
+	"desc.cpu.formula":             "计算过去60分钟内非空闲CPU时间的平均百分比，公式：",
+	"desc.memory.formula":          "计算已使用内存占总内存的百分比，公式：",
+	"desc.disk.formula":            "按分区计算硬盘使用率，当前值、对比值和差值均按分区及其生效的阈值检查，表格中展示使用率最大的分区，公式：",
+	"desc.inode.formula":           "按分区计算inode使用率，当前值、对比值和差值均按分区及其生效的阈值检查，表格中展示使用率最大的分区，公式：",
+	"desc.offset.title":            "2. 对比值（%s前的一个值）",
+	"desc.offset.definition":       "表示系统在 %s 前的一个采样点数据。",
+	"desc.offset.source":           "从 Prometheus 监控系统中查询 %s 前的历史记录，使用相同的查询方式但指定了时间偏移。",
+	"desc.offset.metric":           "与当前值使用相同的计算公式，只是时间点不同。",
+	"desc.delta.title":             "3. 差值（增减率百分比）",
+	"desc.delta.formula":           "当前值%% - %s前值%% = 差值",
+	"desc.delta.meaning":           "反映当前系统状态相较于 %s 前是否发生显著变化，并用百分比表示增减幅度。",
+	"desc.offset.extra":            "另外与 %s 前的值对比，结果表格末尾按对比偏移分组展示对比值及差值。",
+
+	// 巡检结果
+	"section.result":  "服务器巡检结果",
+	"legend.normal":   "巡检使用率正常",
+	"legend.abnormal": "硬盘高于80% or inode使用率高于60% or CPU平均使用率高于60% or 内存平均使用率高于80% or 波动幅度大于10%",
+
+	// 表格列
+	"column.host":        "主机IP",
+	"column.disk":        "硬盘使用率",
+	"column.diskOffset":  "硬盘使用率前%s",
+	"column.diskRate":    "硬盘使用率差值",
+	"column.inode":       "inode使用率",
+	"column.inodeOffset": "inode使用率前%s",
+	"column.inodeRate":   "inode使用率差值",
+	"column.cpu":         "CPU使用率",
+	"column.cpuOffset":   "CPU使用率前%s",
+	"column.cpuRate":     "CPU使用率差值",
+	"column.memory":      "内存使用率",
+	"column.memOffset":   "内存使用率前%s",
+	"column.memRate":     "内存使用率差值",
+	"column.status":      "状态",
+	"column.diskFull":    "预计写满",
+	"column.state":       "采集状态",
+	"column.scrapeAge":   "最近抓取",
+	"column.reason":      "原因",
+	"column.mountpoint":  "挂载点",
+	"column.device":      "设备",
+	"column.fstype":      "文件系统",
+	"column.thresholds":  "阈值(硬盘/inode)",
+
+	// 状态与指标名称
+	"status.normal":       "正常",
+	"status.abnormal":     "异常",
+	"metric.disk":         "硬盘使用率",
+	"metric.inode":        "inode使用率",
+	"metric.cpu":          "CPU使用率",
+	"metric.memory":       "内存使用率",
+	"metric.forecast":     "磁盘写满预测",
+	"metric.reachability": "可达性",
+	"metric.short.disk":   "硬盘",
+	"metric.short.inode":  "inode",
+	"metric.short.cpu":    "CPU",
+	"metric.short.mem":    "内存",
+	"list.separator":      "、",
+
+	// 不可达主机
+	"section.unreachable": "不可达主机（%d台）",
+	"state.ok":            "正常",
+	"state.down":          "宕机",
+	"state.stale":         "数据过期",
+	"state.unknown":       "未知",
+	"reason.down":         "Prometheus抓取失败（up=0）",
+	"reason.noData":       "Prometheus中没有该主机的up指标",
+	"reason.stale":        "最近一次抓取在%s前，超过允许的%s",
+	"reason.queryFailed":  "查询指标失败: %v",
+	"reason.parseFailed":  "解析指标失败: %v",
+
+	// 与上次巡检对比
+	"section.changes":  "与上次巡检对比",
+	"changes.previous": "上次巡检时间: %s",
+	"changes.count":    "新增 %d 项，持续 %d 项，已恢复 %d 项",
+	"changes.none":     "上次和本次巡检均没有异常。",
+	"change.new":       "%s 新增",
+	"change.ongoing":   "%s 持续%s",
+	"change.resolved":  "%s 已恢复",
+	"column.change":    "变化",
+	"column.since":     "首次发现",
+
+	// 差值规则
+	"delta.direction.up":   "上升",
+	"delta.direction.down": "下降",
+	"delta.direction.both": "上升或下降",
+	"delta.absolute":       "%s与对比值相比%s超过%s个百分点",
+	"delta.relative":       "%s相对对比值%s超过%s%%",
+	"delta.baseline":       "（对比值低于%s%%时不检查）",
+
+	// 阈值覆盖
+	"override.applied": "阈值覆盖 %s: %s",
+
+	// 数据源
+	"node.dataSource": "数据源: %s",
+
+	// 动态基线
+	"section.baseline":      "动态基线",
+	"baseline.legend":       "按最近%d天的历史数据计算各项指标的均值和标准差，当前值偏离均值超过%v倍标准差时标记为异常（标准差低于1个百分点时按1个百分点计算）。",
+	"baseline.none":         "历史数据不足，暂时无法计算基线。",
+	"baseline.text":         "偏离基线: %s 当前%s，均值%s，偏离%s",
+	"column.current":        "当前值",
+	"column.baselineMean":   "均值",
+	"column.baselineStddev": "标准差",
+	"column.baselineRange":  "正常范围",
+	"column.deviation":      "偏离",
+
+	// 静默规则
+	"status.acknowledged":     "已确认",
+	"silence.acknowledged":    "%s 已确认: %s（%s，至 %s）",
+	"section.expiredSilences": "已过期的静默规则（%d条）",
+	"silence.expiredHint":     "以下静默规则已过期，不再生效，请负责人续期或从配置中删除。",
+	"summary.acknowledged":    "异常已全部确认的主机 %d 台，不计入异常主机。",
+	"column.metric":           "指标",
+	"column.silenceReason":    "静默原因",
+	"column.owner":            "负责人",
+	"column.expiresAt":        "过期时间",
+
+	// Prometheus查询警告
+	"section.queryWarnings": "Prometheus查询警告（%d条）",
+	"queryWarnings.hint":    "Prometheus在返回结果的同时给出了以下警告，部分数据可能不完整。",
+
+	// 额外对比偏移
+	"offset.group":  "与%s前对比",
+	"offset.column": "%s前%s",
+
+	// 分区明细
+	"filesystem.summary": "分区明细（%d个）",
+
+	// 磁盘写满预测
+	"forecast.days":   "%s天",
+	"forecast.text":   "预计写满: %s %s",
+	"legend.forecast": "按最近%s可用空间的线性回归预测，预计%s天内写满",
+
+	// 趋势图
+	"section.trends":       "24小时趋势",
+	"trends.legend":        "折线为最近24小时的使用率（纵轴0-100%），虚线为阈值，最新值超过阈值时折线标红。硬盘和inode为各分区使用率的最大值，与主机的默认阈值比较，各分区的状态以分区明细为准",
+	"trends.maxFilesystem": "%s（各分区最大值）",
+	"heatmap.title":        "%s热力图",
+	"heatmap.range":        "%s 至 %s",
+	"heatmap.legend":       "每格为对应时段内的最大值，颜色越深越接近阈值，红色表示超过阈值，灰色表示没有数据",
+
+	// 摘要
+	"summary.allNormal":    "共巡检主机 %d 台，全部正常。",
+	"summary.abnormal":     "共巡检主机 %d 台，其中 %d 台异常。",
+	"summary.count":        "巡检主机 %d 台，异常 %d 台",
+	"summary.column.items": "异常项",
+	"summary.attached":     "完整巡检报告请查看邮件附件。",
+	"summary.items":        "异常项: %s",
+
+	// Markdown与纯文本报告
+	"note.markdown": "括号内为与%s前相比的差值，加粗表示超过阈值",
+	"note.text":     "括号内为与%s前相比的差值",
+
+	// 通知
+	"mail.subject":        "%s业务系统巡检报告 - %s",
+	"attachment.filename": "%s巡检报告-%s",
+	"xlsx.sheet":          "巡检结果",
+
+	// 时长
+	"duration.days":      "%d天",
+	"duration.hours":     "%d小时",
+	"duration.minutes":   "%d分钟",
+	"duration.seconds":   "%d秒",
+	"duration.separator": "",
+}
+
+// enUS 英文消息目录
+var enUS = map[string]string{
+	// 报告标题与页脚
+	"report.title":       "%s Inspection Report",
+	"report.generatedAt": "Generated at: %s",
+	"report.footer":      "Generated by the auto inspection system © %s",
+
+	// 巡检说明
+	"section.description":          "About This Report",
+	"desc.values.title":            "How values are calculated",
+	"desc.values.body":             "Using the inspection time as reference, each metric in this report (disk, inode, CPU and memory usage) is calculated as follows:",
+	"desc.selection.title":         "Node selection",
+	"desc.selection.modes.label":   "Two modes:",
+	"desc.selection.modes.body":    "nodes can be listed explicitly or discovered automatically by labels",
+	"desc.selection.nodes.label":   "Node list:",
+	"desc.selection.nodes.body":    "list the addresses directly, e.g. ",
+	"desc.selection.labels.label":  "Label discovery:",
+	"desc.selection.labels.body":   "find matching nodes by labels, e.g. ",
+	"desc.selection.labels.suffix": "; every node carrying these labels is inspected",
+	"desc.selection.mixed.label":   "Mixed mode:",
+	"desc.selection.mixed.body":    "node lists and labels can be configured together and all nodes found are inspected",
+	"desc.label.definition":        "Definition:",
+	"desc.label.source":            "Source:",
+	"desc.label.calculation":       "Calculation:",
+	"desc.label.metric":            "Metric:",
+	"desc.label.formula":           "Formula:",
+	"desc.label.meaning":           "Meaning:",
+	"desc.current.title":           "1. Current value (latest within the last 5 minutes)",
+	"desc.current.definition":      "the latest state of the system within the last 5 minutes.",
+	"desc.current.source":          "instant queries against Prometheus.",
+	"desc.cpu.formula":             "average share of non-idle CPU time over the last 60 minutes: ",
+	"desc.memory.formula":          "share of used memory in total memory: ",
+	"desc.disk.formula":            "usage per filesystem; current values and deltas for every comparison offset are checked per filesystem against its thresholds, and the table shows the fullest filesystem: ",
+	"desc.inode.formula":           "inode usage per filesystem; current values and deltas for every comparison offset are checked per filesystem against its thresholds, and the table shows the filesystem with the most inodes used: ",
+	"desc.offset.title":            "2. Comparison value (a sample from %s ago)",
+	"desc.offset.definition":       "a single sample of the system %s ago.",
+	"desc.offset.source":           "the same Prometheus queries evaluated %s in the past.",
+	"desc.offset.metric":           "calculated exactly like the current value, only at a different time.",
+	"desc.delta.title":             "3. Delta (change in percentage points)",
+	"desc.delta.formula":           "current %% - value %s ago %% = delta",
+	"desc.delta.meaning":           "shows whether the system changed significantly compared with %s ago.",
+	"desc.offset.extra":            "Values are also compared with %s ago; the trailing column groups of the result table show those values and deltas.",
+
+	// 巡检结果
+	"section.result":  "Server Inspection Results",
+	"legend.normal":   "usage within thresholds",
+	"legend.abnormal": "disk above 80%, inode above 60%, average CPU above 60%, average memory above 80% or a change of more than 10%",
+
+	// 表格列
+	"column.host":        "Host",
+	"column.disk":        "Disk usage",
+	"column.diskOffset":  "Disk usage %s ago",
+	"column.diskRate":    "Disk usage delta",
+	"column.inode":       "Inode usage",
+	"column.inodeOffset": "Inode usage %s ago",
+	"column.inodeRate":   "Inode usage delta",
+	"column.cpu":         "CPU usage",
+	"column.cpuOffset":   "CPU usage %s ago",
+	"column.cpuRate":     "CPU usage delta",
+	"column.memory":      "Memory usage",
+	"column.memOffset":   "Memory usage %s ago",
+	"column.memRate":     "Memory usage delta",
+	"column.status":      "Status",
+	"column.diskFull":    "Days until full",
+	"column.state":       "State",
+	"column.scrapeAge":   "Last scrape",
+	"column.reason":      "Reason",
+	"column.mountpoint":  "Mountpoint",
+	"column.device":      "Device",
+	"column.fstype":      "FS type",
+	"column.thresholds":  "Thresholds (disk/inode)",
+
+	// 状态与指标名称
+	"status.normal":       "Normal",
+	"status.abnormal":     "Abnormal",
+	"metric.disk":         "Disk usage",
+	"metric.inode":        "Inode usage",
+	"metric.cpu":          "CPU usage",
+	"metric.memory":       "Memory usage",
+	"metric.forecast":     "Disk full forecast",
+	"metric.reachability": "Reachability",
+	"metric.short.disk":   "Disk",
+	"metric.short.inode":  "Inode",
+	"metric.short.cpu":    "CPU",
+	"metric.short.mem":    "Mem",
+	"list.separator":      ", ",
+
+	// 不可达主机
+	"section.unreachable": "Unreachable Nodes (%d)",
+	"state.ok":            "OK",
+	"state.down":          "Down",
+	"state.stale":         "Stale",
+	"state.unknown":       "Unknown",
+	"reason.down":         "Prometheus failed to scrape the node (up=0)",
+	"reason.noData":       "no up series for the node in Prometheus",
+	"reason.stale":        "last scraped %s ago, more than the allowed %s",
+	"reason.queryFailed":  "failed to query metrics: %v",
+	"reason.parseFailed":  "failed to parse metrics: %v",
+
+	// 与上次巡检对比
+	"section.changes":  "Changes Since Last Run",
+	"changes.previous": "Last run: %s",
+	"changes.count":    "%d new, %d ongoing, %d resolved",
+	"changes.none":     "No anomalies in this run or the last one.",
+	"change.new":       "%s new",
+	"change.ongoing":   "%s ongoing (%s)",
+	"change.resolved":  "%s resolved",
+	"column.change":    "Change",
+	"column.since":     "First seen",
+
+	// 差值规则
+	"delta.direction.up":   "rises",
+	"delta.direction.down": "drops",
+	"delta.direction.both": "rises or drops",
+	"delta.absolute":       "%s %s by more than %s percentage points against the comparison value",
+	"delta.relative":       "%s %s by more than %s%% relative to the comparison value",
+	"delta.baseline":       " (not checked when the comparison value is below %s%%)",
+
+	// 阈值覆盖
+	"override.applied": "Threshold override %s: %s",
+
+	// 数据源
+	"node.dataSource": "Data source: %s",
+
+	// 动态基线
+	"section.baseline":      "Dynamic Baseline",
+	"baseline.legend":       "Mean and standard deviation are computed from the last %d days of history; a value more than %v standard deviations from the mean is abnormal (standard deviations below 1 percentage point count as 1).",
+	"baseline.none":         "Not enough history to compute a baseline yet.",
+	"baseline.text":         "Off baseline: %s at %s, mean %s, deviation %s",
+	"column.current":        "Current",
+	"column.baselineMean":   "Mean",
+	"column.baselineStddev": "Std dev",
+	"column.baselineRange":  "Normal range",
+	"column.deviation":      "Deviation",
+
+	// 静默规则
+	"status.acknowledged":     "Acknowledged",
+	"silence.acknowledged":    "%s acknowledged: %s (%s, until %s)",
+	"section.expiredSilences": "Expired Silences (%d)",
+	"silence.expiredHint":     "These silences have expired and no longer apply. Owners should extend or remove them.",
+	"summary.acknowledged":    "%d hosts have only acknowledged anomalies and are not counted as abnormal.",
+	"column.metric":           "Metric",
+	"column.silenceReason":    "Reason",
+	"column.owner":            "Owner",
+	"column.expiresAt":        "Expires",
+
+	// Prometheus查询警告
+	"section.queryWarnings": "Prometheus Query Warnings (%d)",
+	"queryWarnings.hint":    "Prometheus returned the following warnings alongside the results; some data may be incomplete.",
+
+	// 额外对比偏移
+	"offset.group":  "vs %s ago",
+	"offset.column": "%s vs %s ago",
+
+	// 分区明细
+	"filesystem.summary": "Filesystems (%d)",
+
+	// 磁盘写满预测
+	"forecast.days":   "%s days",
+	"forecast.text":   "Full in: %s %s",
+	"legend.forecast": "disk expected to fill within %[2]s days, by linear regression of free space over the last %[1]s",
+
+	// 趋势图
+	"section.trends":       "24h Trends",
+	"trends.legend":        "Lines show usage over the last 24 hours (0-100%); the dashed line is the threshold and lines turn red when the latest value exceeds it. Disk and inode lines show the highest usage across filesystems against the host's default threshold; see the filesystem details for the status of each filesystem",
+	"trends.maxFilesystem": "%s (max across filesystems)",
+	"heatmap.title":        "%s heatmap",
+	"heatmap.range":        "%s to %s",
+	"heatmap.legend":       "Each cell is the maximum within its time slot; darker cells are closer to the threshold, red cells exceed it and grey cells have no data",
+
+	// 摘要
+	"summary.allNormal":    "%d hosts inspected, all normal.",
+	"summary.abnormal":     "%d hosts inspected, %d abnormal.",
+	"summary.count":        "%d hosts inspected, %d abnormal",
+	"summary.column.items": "Abnormal items",
+	"summary.attached":     "The full report is attached to this email.",
+	"summary.items":        "Abnormal items: %s",
+
+	// Markdown与纯文本报告
+	"note.markdown": "Values in parentheses are deltas against %s ago; bold values exceed thresholds",
+	"note.text":     "Values in parentheses are deltas against %s ago",
+
+	// 通知
+	"mail.subject":        "%s Inspection Report - %s",
+	"attachment.filename": "%s-inspection-report-%s",
+	"xlsx.sheet":          "Inspection Results",
+
+	// 时长
+	"duration.days":      "%dd",
+	"duration.hours":     "%dh",
+	"duration.minutes":   "%dm",
+	"duration.seconds":   "%ds",
+	"duration.separator": " ",
+}
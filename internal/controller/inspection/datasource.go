@@ -0,0 +1,147 @@
+package inspection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultDataSource 只配置prometheusURL时数据源的名称
+const defaultDataSource = "default"
+
+// queryCacheTTL 查询结果的缓存时间，每次巡检使用新的缓存，只用于合并同一次巡检中相同的查询
+const queryCacheTTL = 10 * time.Minute
+
+// dataSource 巡检使用的Prometheus数据源
+type dataSource struct {
+	prometheus.Source
+	// 主机名称匹配规则，为空时匹配所有主机
+	nodes *regexp.Regexp
+}
+
+// newDataSources 根据spec.dataSources创建数据源，未配置时使用prometheusURL作为唯一的主数据源
+func newDataSources(spec devopsv1.AutoInspectionSpec) ([]*dataSource, error) {
+	configs := spec.DataSources
+	if len(configs) == 0 {
+		if spec.PrometheusURL == "" {
+			return nil, fmt.Errorf("prometheusURL和dataSources最少配置一个")
+		}
+		configs = []devopsv1.DataSource{{Name: defaultDataSource, URL: spec.PrometheusURL}}
+	}
+
+	cache := prometheus.NewQueryCache(queryCacheTTL)
+	sources := make([]*dataSource, 0, len(configs))
+	names := make(map[string]struct{}, len(configs))
+	for _, config := range configs {
+		if _, ok := names[config.Name]; ok {
+			return nil, fmt.Errorf("数据源名称重复: %s", config.Name)
+		}
+		names[config.Name] = struct{}{}
+
+		client := prometheus.NewClient(config.URL)
+		client.Retry = retryPolicy(spec.QueryRetry)
+		client.Cache = cache
+		client.Backend = prometheus.BackendOptions{
+			Type:            prometheus.Backend(config.Type),
+			PathPrefix:      config.PathPrefix,
+			Tenant:          config.Tenant,
+			Dedup:           config.Dedup,
+			PartialResponse: config.PartialResponse,
+		}
+		source := &dataSource{Source: prometheus.Source{
+			Name:     config.Name,
+			Fallback: config.Role == devopsv1.DataSourceRoleFallback,
+			Client:   client,
+		}}
+		if config.Nodes != "" {
+			nodes, err := regexp.Compile("^(?:" + config.Nodes + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("数据源%s的主机匹配规则无效: %w", config.Name, err)
+			}
+			source.nodes = nodes
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// matches 主机是否使用该数据源
+func (d *dataSource) matches(node string) bool {
+	return d.nodes == nil || d.nodes.MatchString(node)
+}
+
+// discoverNodes 在所有数据源上按标签查询主机并合并结果，同时返回每台主机由哪些数据源返回。
+// 部分数据源查询失败时只记录日志，全部失败时返回错误
+func (i *Inspector) discoverNodes(ctx context.Context, matchers []prometheus.Matcher) ([]string, map[string][]*dataSource, error) {
+	logger := log.FromContext(ctx)
+
+	discovered := make(map[string][]*dataSource)
+	var errs []error
+	for _, source := range i.dataSources {
+		nodes, err := source.Client.GetNodesByLabels(ctx, matchers)
+		if err != nil {
+			logger.Error(err, "通过标签查询节点失败", "dataSource", source.Name)
+			errs = append(errs, fmt.Errorf("数据源%s: %w", source.Name, err))
+			continue
+		}
+		for _, node := range nodes {
+			if source.matches(node) {
+				discovered[node] = append(discovered[node], source)
+			}
+		}
+	}
+	if len(discovered) == 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	nodes := make([]string, 0, len(discovered))
+	for node := range discovered {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes, discovered, nil
+}
+
+// failoverFor 返回主机使用的数据源，按标签查询到的主机只使用返回了该主机的数据源
+func (i *Inspector) failoverFor(node string, discovered map[string][]*dataSource) *prometheus.Failover {
+	candidates := i.dataSources
+	if sources, ok := discovered[node]; ok {
+		candidates = sources
+	}
+
+	var sources []*prometheus.Source
+	for _, source := range candidates {
+		if source.matches(node) {
+			sources = append(sources, &source.Source)
+		}
+	}
+	return prometheus.NewFailover(sources...)
+}
+
+// queryWarnings 汇总各数据源返回的警告
+func (i *Inspector) queryWarnings() []string {
+	var warnings []string
+	for _, source := range i.dataSources {
+		for _, warning := range source.Client.Warnings() {
+			if !slices.Contains(warnings, warning) {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	return warnings
+}
+
+// servedBy 返回报告中标注的主机数据来源，只有一个数据源时不标注
+func (i *Inspector) servedBy(client *prometheus.Failover) string {
+	if len(i.dataSources) < 2 {
+		return ""
+	}
+	return client.Source()
+}
@@ -0,0 +1,276 @@
+package inspection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/prometheus"
+)
+
+// instancePattern 查询中的instance匹配器
+var instancePattern = regexp.MustCompile(`instance="([^"]+)"`)
+
+// fakePrometheus 模拟只抓取了指定主机的Prometheus，status不为200时返回该状态码。
+// 查询包含instance匹配器时只返回该主机的up时间序列
+func fakePrometheus(status int, instances ...string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		matched := instances
+		if match := instancePattern.FindStringSubmatch(r.FormValue("query")); match != nil {
+			matched = nil
+			if slices.Contains(instances, match[1]) {
+				matched = []string{match[1]}
+			}
+		}
+		result := make([]map[string]any, 0, len(matched))
+		for _, instance := range matched {
+			result = append(result, map[string]any{
+				"metric": map[string]string{"__name__": "up", "instance": instance},
+				"value":  []any{1700000000, "1"},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data":   map[string]any{"resultType": "vector", "result": result},
+		})
+	}))
+	DeferCleanup(server.Close)
+	return server.URL
+}
+
+// newTestInspector 使用给定数据源创建巡检器，查询失败时不重试
+func newTestInspector(configs ...devopsv1.DataSource) *Inspector {
+	sources, err := newDataSources(devopsv1.AutoInspectionSpec{
+		DataSources: configs,
+		QueryRetry:  &devopsv1.QueryRetry{MaxAttempts: 1},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return &Inspector{dataSources: sources}
+}
+
+// sourceNames 返回数据源名称
+func sourceNames(sources []*dataSource) []string {
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.Name)
+	}
+	return names
+}
+
+var _ = Describe("Data sources", func() {
+	Describe("newDataSources", func() {
+		DescribeTable("should reject invalid configurations",
+			func(spec devopsv1.AutoInspectionSpec, message string) {
+				_, err := newDataSources(spec)
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("without prometheusURL and dataSources",
+				devopsv1.AutoInspectionSpec{}, "prometheusURL和dataSources最少配置一个"),
+			Entry("with duplicate names",
+				devopsv1.AutoInspectionSpec{DataSources: []devopsv1.DataSource{
+					{Name: "shanghai", URL: "http://prometheus-a:9090"},
+					{Name: "shanghai", URL: "http://prometheus-b:9090"},
+				}}, "数据源名称重复: shanghai"),
+			Entry("with an invalid node pattern",
+				devopsv1.AutoInspectionSpec{DataSources: []devopsv1.DataSource{
+					{Name: "shanghai", URL: "http://prometheus-a:9090", Nodes: "web-("},
+				}}, "数据源shanghai的主机匹配规则无效"),
+		)
+
+		It("should use prometheusURL as the only primary data source", func() {
+			sources, err := newDataSources(devopsv1.AutoInspectionSpec{PrometheusURL: "http://prometheus:9090"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sources).To(HaveLen(1))
+			Expect(sources[0].Name).To(Equal(defaultDataSource))
+			Expect(sources[0].Fallback).To(BeFalse())
+			Expect(sources[0].Client.URL).To(Equal("http://prometheus:9090"))
+			Expect(sources[0].Client.Retry).To(Equal(prometheus.DefaultRetryPolicy()))
+		})
+
+		It("should ignore prometheusURL and configure each data source", func() {
+			dedup := false
+			sources, err := newDataSources(devopsv1.AutoInspectionSpec{
+				PrometheusURL: "http://prometheus:9090",
+				QueryRetry:    &devopsv1.QueryRetry{MaxAttempts: 5},
+				DataSources: []devopsv1.DataSource{
+					{Name: "thanos", URL: "http://thanos-query:9090", Type: devopsv1.DataSourceTypeThanos, Tenant: "team-a", Dedup: &dedup},
+					{Name: "replica", URL: "http://prometheus-replica:9090", Role: devopsv1.DataSourceRoleFallback, Nodes: "web-.*"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sourceNames(sources)).To(Equal([]string{"thanos", "replica"}))
+
+			Expect(sources[0].Fallback).To(BeFalse())
+			Expect(sources[0].Client.Backend).To(Equal(prometheus.BackendOptions{
+				Type:   prometheus.BackendThanos,
+				Tenant: "team-a",
+				Dedup:  &dedup,
+			}))
+			Expect(sources[1].Fallback).To(BeTrue())
+			Expect(sources[1].Client.Retry.MaxAttempts).To(Equal(5))
+			// 同一次巡检的数据源共享查询缓存
+			Expect(sources[0].Client.Cache).NotTo(BeNil())
+			Expect(sources[1].Client.Cache).To(BeIdenticalTo(sources[0].Client.Cache))
+		})
+
+		DescribeTable("should match node names completely",
+			func(pattern, node string, matches bool) {
+				sources, err := newDataSources(devopsv1.AutoInspectionSpec{DataSources: []devopsv1.DataSource{
+					{Name: "shanghai", URL: "http://prometheus:9090", Nodes: pattern},
+				}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sources[0].matches(node)).To(Equal(matches))
+			},
+			Entry("without a pattern", "", "10.0.0.1:9100", true),
+			Entry("with a matching pattern", `10\.0\.1\..*`, "10.0.1.5:9100", true),
+			Entry("with a prefix of the node name", "web", "web-1", false),
+			Entry("with an alternation", "web-1|db-1", "db-1", true),
+		)
+	})
+
+	Describe("discoverNodes", func() {
+		matchers := []prometheus.Matcher{{Name: "env", Type: prometheus.MatchEqual, Value: "prod"}}
+
+		DescribeTable("should merge the nodes of every data source",
+			func(configs func() []devopsv1.DataSource, nodes []string, servedBy map[string][]string) {
+				inspector := newTestInspector(configs()...)
+				discoveredNodes, discovered, err := inspector.discoverNodes(context.Background(), matchers)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(discoveredNodes).To(Equal(nodes))
+
+				names := make(map[string][]string, len(discovered))
+				for node, sources := range discovered {
+					names[node] = sourceNames(sources)
+				}
+				Expect(names).To(Equal(servedBy))
+			},
+			Entry("from shards and replicas", func() []devopsv1.DataSource {
+				return []devopsv1.DataSource{
+					{Name: "shanghai", URL: fakePrometheus(http.StatusOK, "sh-1", "sh-2")},
+					{Name: "beijing", URL: fakePrometheus(http.StatusOK, "bj-1")},
+					{Name: "replica", URL: fakePrometheus(http.StatusOK, "sh-1"), Role: devopsv1.DataSourceRoleFallback},
+				}
+			}, []string{"bj-1", "sh-1", "sh-2"}, map[string][]string{
+				"bj-1": {"beijing"},
+				"sh-1": {"shanghai", "replica"},
+				"sh-2": {"shanghai"},
+			}),
+			Entry("skipping nodes that do not match the data source", func() []devopsv1.DataSource {
+				return []devopsv1.DataSource{
+					{Name: "shanghai", URL: fakePrometheus(http.StatusOK, "sh-1", "bj-1"), Nodes: "sh-.*"},
+					{Name: "beijing", URL: fakePrometheus(http.StatusOK, "bj-1")},
+				}
+			}, []string{"bj-1", "sh-1"}, map[string][]string{
+				"bj-1": {"beijing"},
+				"sh-1": {"shanghai"},
+			}),
+			Entry("when some data sources fail", func() []devopsv1.DataSource {
+				return []devopsv1.DataSource{
+					{Name: "shanghai", URL: fakePrometheus(http.StatusServiceUnavailable)},
+					{Name: "beijing", URL: fakePrometheus(http.StatusOK, "bj-1")},
+				}
+			}, []string{"bj-1"}, map[string][]string{
+				"bj-1": {"beijing"},
+			}),
+		)
+
+		It("should return an error when every data source fails", func() {
+			inspector := newTestInspector(
+				devopsv1.DataSource{Name: "shanghai", URL: fakePrometheus(http.StatusServiceUnavailable)},
+				devopsv1.DataSource{Name: "beijing", URL: fakePrometheus(http.StatusOK)},
+			)
+			_, _, err := inspector.discoverNodes(context.Background(), matchers)
+			Expect(err).To(MatchError(ContainSubstring("数据源shanghai")))
+			Expect(err).To(MatchError(ContainSubstring("数据源beijing")))
+		})
+	})
+
+	Describe("failoverFor", func() {
+		var inspector *Inspector
+
+		BeforeEach(func() {
+			inspector = newTestInspector(
+				devopsv1.DataSource{Name: "shanghai", URL: fakePrometheus(http.StatusOK, "sh-1", "web-1"), Nodes: "sh-.*|web-.*"},
+				devopsv1.DataSource{Name: "beijing", URL: fakePrometheus(http.StatusOK, "bj-1", "web-2")},
+				devopsv1.DataSource{Name: "replica", URL: fakePrometheus(http.StatusOK, "sh-1", "bj-1", "db-1"), Role: devopsv1.DataSourceRoleFallback},
+			)
+		})
+
+		// discoveredBy 模拟按标签查询到的主机及返回该主机的数据源
+		discoveredBy := func(node string, names ...string) map[string][]*dataSource {
+			var sources []*dataSource
+			for _, source := range inspector.dataSources {
+				if slices.Contains(names, source.Name) {
+					sources = append(sources, source)
+				}
+			}
+			return map[string][]*dataSource{node: sources}
+		}
+
+		DescribeTable("should query the data source that scrapes the node",
+			func(node string, discovered func() map[string][]*dataSource, expected string) {
+				client := inspector.failoverFor(node, discovered())
+				result, err := client.Query(context.Background(), `up{instance="`+node+`"}`, time.Time{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Data.Result).To(HaveLen(1))
+				Expect(client.Source()).To(Equal(expected))
+			},
+			Entry("listed in nodes and scraped by the first primary", "sh-1",
+				func() map[string][]*dataSource { return nil }, "shanghai"),
+			Entry("listed in nodes and scraped by a later primary", "web-2",
+				func() map[string][]*dataSource { return nil }, "beijing"),
+			Entry("discovered by labels", "bj-1",
+				func() map[string][]*dataSource { return discoveredBy("bj-1", "beijing", "replica") }, "beijing"),
+		)
+
+		It("should not query the fallback when every primary returns no data", func() {
+			client := inspector.failoverFor("db-1", nil)
+			result, err := client.Query(context.Background(), `up{instance="db-1"}`, time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Empty()).To(BeTrue())
+			Expect(client.Source()).To(Equal("beijing"))
+		})
+
+		It("should only use the data sources that returned a discovered node", func() {
+			client := inspector.failoverFor("sh-1", discoveredBy("sh-1", "replica"))
+			Expect(client.Source()).To(Equal("replica"))
+		})
+
+		It("should skip data sources whose node pattern does not match", func() {
+			client := inspector.failoverFor("bj-1", nil)
+			Expect(client.Source()).To(Equal("beijing"))
+		})
+	})
+
+	Describe("servedBy", func() {
+		It("should not annotate nodes with a single data source", func() {
+			inspector := newTestInspector(devopsv1.DataSource{Name: "default", URL: fakePrometheus(http.StatusOK, "sh-1")})
+			Expect(inspector.servedBy(inspector.failoverFor("sh-1", nil))).To(BeEmpty())
+		})
+
+		It("should return the data source currently serving the node", func() {
+			inspector := newTestInspector(
+				devopsv1.DataSource{Name: "shanghai", URL: fakePrometheus(http.StatusOK, "sh-1")},
+				devopsv1.DataSource{Name: "beijing", URL: fakePrometheus(http.StatusOK, "bj-1")},
+			)
+			client := inspector.failoverFor("bj-1", nil)
+			Expect(inspector.servedBy(client)).To(Equal("shanghai"))
+
+			_, err := client.Query(context.Background(), `up{instance="bj-1"}`, time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inspector.servedBy(client)).To(Equal("beijing"))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package inspection
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// 巡检相关Kubernetes事件的Reason
+const (
+	// EventRunStarted 开始执行巡检任务
+	EventRunStarted = "RunStarted"
+	// EventRunCompleted 巡检任务执行完成
+	EventRunCompleted = "RunCompleted"
+	// EventRunFailed 巡检任务执行失败
+	EventRunFailed = "RunFailed"
+	// EventRunSkipped 到期的巡检任务未执行
+	EventRunSkipped = "RunSkipped"
+	// EventInvalidSchedule 巡检任务的Cron表达式无效
+	EventInvalidSchedule = "InvalidSchedule"
+	// EventNodeCollectionFailed 部分主机的指标采集失败
+	EventNodeCollectionFailed = "NodeCollectionFailed"
+	// EventNotificationFailed 通知发送失败
+	EventNotificationFailed = "NotificationFailed"
+	// EventNotificationSkipped 按通知策略跳过通知
+	EventNotificationSkipped = "NotificationSkipped"
+)
+
+// maxEventNodes 事件消息中最多列出的主机数，避免主机较多时消息过长
+const maxEventNodes = 5
+
+// WithEventRecorder 将巡检过程中的采集失败、通知失败和巡检结果记录为AutoInspection的事件
+func WithEventRecorder(recorder record.EventRecorder) Option {
+	return func(i *Inspector) {
+		i.recorder = recorder
+	}
+}
+
+// event 记录AutoInspection的事件，未配置EventRecorder时忽略
+func (i *Inspector) event(eventType, reason, messageFmt string, args ...interface{}) {
+	if i.recorder == nil {
+		return
+	}
+	i.recorder.Eventf(i.inspection, eventType, reason, messageFmt, args...)
+}
+
+// nodeCollectionFailed 汇总指标采集失败的主机，每次巡检最多记录一条事件
+func (i *Inspector) nodeCollectionFailed(nodes []string) {
+	if len(nodes) == 0 {
+		return
+	}
+	i.event(corev1.EventTypeWarning, EventNodeCollectionFailed, "%d台主机指标采集失败: %s", len(nodes), joinNodes(nodes))
+}
+
+// joinNodes 拼接主机名称，超过maxEventNodes时只列出前几台
+func joinNodes(nodes []string) string {
+	if len(nodes) <= maxEventNodes {
+		return strings.Join(nodes, ", ")
+	}
+	return fmt.Sprintf("%s 等", strings.Join(nodes[:maxEventNodes], ", "))
+}
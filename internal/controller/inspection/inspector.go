@@ -2,29 +2,72 @@ package inspection
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html/template"
 	"math"
+	"sort"
 	"time"
 
 	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
 	"github.com/rxg456/auto-inspection-operator/internal/controller/mail"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/metrics"
 	"github.com/rxg456/auto-inspection-operator/internal/controller/prometheus"
 	"github.com/rxg456/auto-inspection-operator/internal/controller/report"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/tracing"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/webhook"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // Inspector 巡检器
 type Inspector struct {
-	prometheusClient *prometheus.Client
-	reportGenerator  *report.Generator
-	mailSender       *mail.Sender
-	inspection       *devopsv1.AutoInspection
+	// Prometheus数据源，按配置顺序排列
+	dataSources     []*dataSource
+	reportGenerator *report.Generator
+	mailSender      *mail.Sender
+	webhookSender   *webhook.Sender
+	inspection      *devopsv1.AutoInspection
+	// 巡检历史存储，为空时不与上次巡检对比
+	history HistoryStore
+	// 记录巡检事件，为空时不记录
+	recorder record.EventRecorder
+}
+
+// HistoryStore 保存巡检任务上次的结果，用于标记新增、持续和已恢复的异常
+type HistoryStore interface {
+	// Load 读取上次的巡检结果，没有记录时返回nil
+	Load(ctx context.Context) (*report.Snapshot, error)
+	// Save 保存本次的巡检结果
+	Save(ctx context.Context, snapshot *report.Snapshot) error
+}
+
+// Option 巡检器选项
+type Option func(*Inspector)
+
+// WithReportTemplate 使用自定义报告模板生成HTML报告
+func WithReportTemplate(tmpl *template.Template) Option {
+	return func(i *Inspector) {
+		i.reportGenerator = report.NewGenerator(report.WithTemplate(tmpl))
+	}
+}
+
+// WithHistory 与上次巡检结果对比，巡检成功后保存本次结果
+func WithHistory(store HistoryStore) Option {
+	return func(i *Inspector) {
+		i.history = store
+	}
 }
 
 // NewInspector 创建巡检器
-func NewInspector(inspection *devopsv1.AutoInspection) (*Inspector, error) {
-	// 创建Prometheus客户端
-	prometheusClient := prometheus.NewClient(inspection.Spec.PrometheusURL)
+func NewInspector(inspection *devopsv1.AutoInspection, opts ...Option) (*Inspector, error) {
+	// 创建各数据源的Prometheus客户端
+	dataSources, err := newDataSources(inspection.Spec)
+	if err != nil {
+		return nil, err
+	}
 
 	// 创建报告生成器
 	reportGenerator := report.NewGenerator()
@@ -32,16 +75,49 @@ func NewInspector(inspection *devopsv1.AutoInspection) (*Inspector, error) {
 	// 创建邮件发送器
 	mailSender := mail.NewSender(inspection.Spec.SMTP)
 
-	return &Inspector{
-		prometheusClient: prometheusClient,
-		reportGenerator:  reportGenerator,
-		mailSender:       mailSender,
-		inspection:       inspection,
-	}, nil
+	inspector := &Inspector{
+		dataSources:     dataSources,
+		reportGenerator: reportGenerator,
+		mailSender:      mailSender,
+		webhookSender:   webhook.NewSender(),
+		inspection:      inspection,
+	}
+	for _, opt := range opts {
+		opt(inspector)
+	}
+
+	return inspector, nil
+}
+
+// retryPolicy 将spec.queryRetry转换为Prometheus客户端的重试策略，未配置的字段使用默认值
+func retryPolicy(spec *devopsv1.QueryRetry) prometheus.RetryPolicy {
+	policy := prometheus.DefaultRetryPolicy()
+	if spec == nil {
+		return policy
+	}
+	if spec.MaxAttempts > 0 {
+		policy.MaxAttempts = int(spec.MaxAttempts)
+	}
+	if spec.InitialBackoff != nil && spec.InitialBackoff.Duration > 0 {
+		policy.InitialBackoff = spec.InitialBackoff.Duration
+	}
+	if spec.MaxBackoff != nil && spec.MaxBackoff.Duration > 0 {
+		policy.MaxBackoff = spec.MaxBackoff.Duration
+	}
+	return policy
 }
 
 // RunInspection 执行巡检
 func (i *Inspector) RunInspection(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "RunInspection",
+		tracing.AttrNamespace.String(i.inspection.Namespace), tracing.AttrName.String(i.inspection.Name))
+	err := i.runInspection(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+// runInspection 采集各主机的指标，生成报告并发送通知
+func (i *Inspector) runInspection(ctx context.Context) error {
 	logger := log.FromContext(ctx)
 	logger.Info("开始执行巡检")
 
@@ -49,18 +125,29 @@ func (i *Inspector) RunInspection(ctx context.Context) error {
 
 	// 当前时间
 	now := time.Now()
-	// 24小时前
+	// 24小时前，趋势图的起点
 	dayAgo := now.Add(-24 * time.Hour)
 
+	// 对比偏移，第一个为主对比偏移
+	comparisons, err := i.comparisons()
+	if err != nil {
+		return err
+	}
+	offsetTime := now.Add(-comparisons[0].Duration)
+
 	// 获取需要巡检的节点列表
 	nodes := i.inspection.Spec.InspectionObject.Hosts.Nodes
 	labels := i.inspection.Spec.InspectionObject.Hosts.Labels
+	matchers, err := prometheus.ParseMatchers(labels)
+	if err != nil {
+		return fmt.Errorf("解析主机标签失败: %w", err)
+	}
 
-	// 当nodes为空但labels不为空时，通过labels查询节点
+	// 当nodes为空但labels不为空时，通过labels在所有数据源上查询节点
+	var discovered map[string][]*dataSource
 	if len(nodes) == 0 && len(labels) > 0 {
 		logger.Info("节点列表为空但有标签，尝试通过标签查询节点", "labels", labels)
-		var err error
-		nodes, err = i.prometheusClient.GetNodesByLabels(ctx, labels)
+		nodes, discovered, err = i.discoverNodes(ctx, matchers)
 		if err != nil {
 			logger.Error(err, "通过标签查询节点失败")
 			return fmt.Errorf("通过标签查询节点失败: %w", err)
@@ -74,58 +161,901 @@ func (i *Inspector) RunInspection(ctx context.Context) error {
 		return fmt.Errorf("节点列表为空，无法执行巡检")
 	}
 
-	// 获取所有主机的指标
+	// 磁盘写满预测配置
+	forecast := i.forecastConfig()
+
+	// 动态基线配置
+	baseline, err := i.baselineConfig()
+	if err != nil {
+		return err
+	}
+
+	// 阈值规则，按主机覆盖的规则在检查阈值前合并
+	thresholds := i.thresholds()
+	overrides, groups, err := i.thresholdOverrides()
+	if err != nil {
+		return err
+	}
+
+	// 获取所有主机的指标，采集失败的主机汇总后记录为一条事件
+	catalog := i18n.NewCatalog(i.inspection.Spec.Language)
+	var failedNodes []string
 	for _, node := range nodes {
 		logger.Info("巡检主机", "node", node)
+		nodeCtx, span := tracing.Start(ctx, "CollectNode", tracing.AttrNode.String(node))
+		client := i.failoverFor(node, discovered)
+
+		// 宕机或数据过期的主机不再查询指标，直接作为严重异常写入报告
+		health, targetLabels := i.checkNodeHealth(nodeCtx, client, node, matchers, now)
+		if health.State != report.NodeStateOK {
+			logger.Info("主机不可达", "node", node, "state", health.State, "reason", health.Reason)
+			unreachable := report.NewUnreachableNode(node, health)
+			unreachable.Labels = targetLabels
+			unreachable.DataSource = i.servedBy(client)
+			nodeMetrics = append(nodeMetrics, unreachable)
+			failedNodes = append(failedNodes, node)
+			span.SetAttributes(tracing.AttrNodeState.String(health.State), tracing.AttrDataSource.String(client.Source()))
+			span.End()
+			continue
+		}
 
-		metrics, err := i.collectNodeMetrics(ctx, node, labels, now, dayAgo)
+		metrics, err := i.collectNodeMetrics(nodeCtx, client, node, matchers, now, offsetTime)
 		if err != nil {
-			logger.Error(err, "获取主机指标失败", "node", node)
+			logger.Error(err, "获取主机指标失败", "node", node, "dataSource", client.Source())
+			failed := report.NewUnreachableNode(node, report.NodeHealth{
+				State:     report.NodeStateUnknown,
+				ScrapeAge: health.ScrapeAge,
+				Reason:    queryReason(catalog, err),
+			})
+			failed.DataSource = i.servedBy(client)
+			nodeMetrics = append(nodeMetrics, failed)
+			failedNodes = append(failedNodes, node)
+			tracing.End(span, err)
 			continue
 		}
+		metrics.Health = health
+		metrics.Labels = targetLabels
+		metrics.DataSource = i.servedBy(client)
+		metrics.Thresholds = report.ResolveThresholds(thresholds, node, targetLabels, groups, overrides)
+		if len(metrics.Thresholds.Sources) > 0 {
+			logger.Info("使用阈值覆盖", "node", node, "sources", metrics.Thresholds.Sources)
+		}
+		metrics.Offsets = i.collectOffsets(nodeCtx, client, node, matchers, now, metrics, comparisons[1:])
+
+		// 采集趋势图数据，失败时只影响趋势图
+		if i.inspection.Spec.Report.Charts {
+			metrics.Trends = i.collectNodeTrends(nodeCtx, client, node, matchers, dayAgo, now)
+		}
+
+		// 预测磁盘写满时间，失败时只影响预测列
+		if forecast != nil {
+			metrics.DiskForecast = i.collectDiskForecast(nodeCtx, client, node, matchers, now, forecast.Window)
+		}
+
+		// 计算动态基线，失败时只影响该指标的基线
+		if baseline != nil {
+			metrics.Baselines = i.collectBaselines(nodeCtx, client, node, matchers, now, baseline)
+		}
 
 		// 检查阈值并设置状态
-		report.CheckThresholds(metrics)
+		report.CheckThresholds(metrics, comparisons...)
+		if forecast != nil {
+			report.CheckForecast(metrics, forecast.ThresholdDays)
+		}
+		if baseline != nil {
+			report.CheckBaseline(metrics, baseline.Sigma)
+		}
 		nodeMetrics = append(nodeMetrics, *metrics)
+		span.SetAttributes(tracing.AttrDataSource.String(client.Source()))
+		span.End()
 	}
+	i.nodeCollectionFailed(failedNodes)
 
 	// 生成报告数据
-	reportData, err := report.GenerateReport(i.inspection.Spec.InspectionObject.Business, nodeMetrics)
+	reportData, err := report.GenerateReport(i.inspection.Spec.InspectionObject.Business, nodeMetrics, i.inspection.Spec.Language)
 	if err != nil {
 		return fmt.Errorf("生成报告数据失败: %w", err)
 	}
+	reportData.Inspection.Forecast = forecast
+	reportData.Inspection.Baseline = baseline
+	reportData.Inspection.Comparisons = comparisons
+	reportData.Inspection.Thresholds = thresholds
+	reportData.QueryWarnings = i.queryWarnings()
+	for _, warning := range reportData.QueryWarnings {
+		logger.Info("Prometheus查询警告", "warning", warning)
+	}
 
-	// 生成HTML报告
-	htmlReport, err := i.reportGenerator.GenerateHTML(reportData)
+	// 按静默规则确认已知异常
+	if err := report.ApplySilences(reportData, i.silences(), now); err != nil {
+		return err
+	}
+	for _, silence := range reportData.ExpiredSilences {
+		logger.Info("静默规则已过期", "target", silence.Target(), "metric", silence.Metric,
+			"owner", silence.Owner, "expiresAt", silence.ExpiresAt)
+	}
+
+	// 导出巡检结果指标，通知发送失败不影响已导出的结果
+	i.exportFindings(reportData)
+
+	// 与上次巡检对比，读取历史失败时报告中不展示变化，也不保存本次结果，
+	// 避免覆盖已保存的历史后所有持续异常被当作新增异常
+	var snapshot *report.Snapshot
+	if i.history != nil {
+		previous, err := i.history.Load(ctx)
+		if err != nil {
+			logger.Error(err, "读取巡检历史失败，本次不对比也不保存历史")
+		} else {
+			snapshot = report.CompareHistory(reportData, previous)
+		}
+	}
+
+	// 只在有异常时通知，没有未确认的异常时跳过通知，仍然保存历史
+	if i.inspection.Spec.NotifyPolicy == devopsv1.NotifyPolicyOnAnomaly && !reportData.HasAnomalies() {
+		logger.Info("没有未确认的异常，按通知策略跳过通知", "acknowledged", len(reportData.AcknowledgedNodes()))
+		i.event(corev1.EventTypeNormal, EventNotificationSkipped, "没有未确认的异常，按通知策略%s跳过通知", devopsv1.NotifyPolicyOnAnomaly)
+		i.saveHistory(ctx, snapshot)
+		i.runCompleted(reportData)
+		logger.Info("巡检任务完成")
+		return nil
+	}
+
+	// 生成邮件正文和附件
+	body, attachments, err := i.generateReport(ctx, reportData)
 	if err != nil {
-		return fmt.Errorf("生成HTML报告失败: %w", err)
+		return err
 	}
 
+	// 各通知渠道互不影响，全部发送后汇总错误
+	var errs []error
+
 	// 发送邮件
-	subject := fmt.Sprintf("%s业务系统巡检报告 - %s", i.inspection.Spec.InspectionObject.Business, time.Now().Format("2006-01-02"))
-	err = i.mailSender.SendMail(i.inspection.Spec.NotifyTo, subject, htmlReport)
-	if err != nil {
-		return fmt.Errorf("发送邮件失败: %w", err)
+	if err := i.sendMail(ctx, reportData, body, attachments); err != nil {
+		i.event(corev1.EventTypeWarning, EventNotificationFailed, "发送邮件失败: %v", err)
+		errs = append(errs, fmt.Errorf("发送邮件失败: %w", err))
 	}
 
+	// 发送Webhook通知
+	for _, hook := range i.inspection.Spec.Webhooks {
+		if err := i.sendWebhook(ctx, hook, reportData); err != nil {
+			logger.Error(err, "发送Webhook失败", "webhook", hook.Name)
+			i.event(corev1.EventTypeWarning, EventNotificationFailed, "发送Webhook %s失败: %v", hook.Name, err)
+			errs = append(errs, fmt.Errorf("发送Webhook %s失败: %w", hook.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	i.saveHistory(ctx, snapshot)
+	i.runCompleted(reportData)
+
 	logger.Info("巡检任务完成")
 	return nil
 }
 
+// generateReport 生成HTML报告和附件，配置了附件时邮件正文只展示摘要
+func (i *Inspector) generateReport(ctx context.Context, reportData *report.ReportData) (body string, attachments []mail.Attachment, err error) {
+	_, span := tracing.Start(ctx, "GenerateReport")
+	defer func() { tracing.End(span, err) }()
+
+	// 生成HTML报告
+	htmlReport, err := i.reportGenerator.GenerateHTML(reportData)
+	if err != nil {
+		return "", nil, fmt.Errorf("生成HTML报告失败: %w", err)
+	}
+
+	// 生成附件
+	body = htmlReport
+	attachments, err = i.buildAttachments(reportData, htmlReport)
+	if err != nil {
+		return "", nil, fmt.Errorf("生成报告附件失败: %w", err)
+	}
+	if len(attachments) > 0 {
+		body, err = i.reportGenerator.GenerateSummaryHTML(reportData)
+		if err != nil {
+			return "", nil, fmt.Errorf("生成报告摘要失败: %w", err)
+		}
+	}
+	return body, attachments, nil
+}
+
+// sendMail 发送巡检报告邮件
+func (i *Inspector) sendMail(ctx context.Context, reportData *report.ReportData, body string, attachments []mail.Attachment) error {
+	_, span := tracing.Start(ctx, "Notify", tracing.AttrChannel.String(metrics.ChannelMail))
+	subject := reportData.Catalog().T("mail.subject", reportData.Metadata.Business, reportData.Metadata.Date)
+	err := i.mailSender.SendMail(i.inspection.Spec.NotifyTo, subject, body, attachments...)
+	tracing.End(span, err)
+	return err
+}
+
+// runCompleted 记录巡检完成事件，存在未确认的异常时为Warning
+func (i *Inspector) runCompleted(reportData *report.ReportData) {
+	abnormal := len(reportData.AbnormalNodes())
+	eventType := corev1.EventTypeNormal
+	if abnormal > 0 {
+		eventType = corev1.EventTypeWarning
+	}
+	i.event(eventType, EventRunCompleted, "巡检完成，共%d台主机，%d台存在异常，%d台异常已确认",
+		len(reportData.Inspection.Node), abnormal, len(reportData.AcknowledgedNodes()))
+}
+
+// exportFindings 按spec.findingMetrics导出本次巡检的结果，替换上次导出的时间序列
+func (i *Inspector) exportFindings(reportData *report.ReportData) {
+	namespace, name := i.inspection.Namespace, i.inspection.Name
+	config := i.inspection.Spec.FindingMetrics
+	if config != nil && config.Disabled {
+		metrics.Findings.Delete(namespace, name)
+		return
+	}
+
+	filter := report.FindingFilter{MaxNodes: report.DefaultFindingMaxNodes}
+	if config != nil {
+		filter.Metrics = config.Metrics
+		filter.AbnormalOnly = config.AbnormalOnly
+		if config.MaxNodes > 0 {
+			filter.MaxNodes = int(config.MaxNodes)
+		}
+	}
+	findings, dropped := reportData.Findings(filter)
+	metrics.Findings.Set(namespace, name, findings, dropped)
+}
+
+// saveHistory 保存本次巡检的快照。
+// 只在巡检成功后保存，避免重试时把本次的新增异常误判为持续异常
+func (i *Inspector) saveHistory(ctx context.Context, snapshot *report.Snapshot) {
+	if snapshot == nil {
+		return
+	}
+	if err := i.history.Save(ctx, snapshot); err != nil {
+		log.FromContext(ctx).Error(err, "保存巡检历史失败")
+	}
+}
+
+// buildAttachments 按配置生成报告附件
+func (i *Inspector) buildAttachments(reportData *report.ReportData, htmlReport string) ([]mail.Attachment, error) {
+	formats := i.inspection.Spec.Report.Attachments
+	if len(formats) == 0 {
+		return nil, nil
+	}
+
+	// 附件名中的日期固定使用数字格式，避免出现空格和逗号
+	filename := reportData.Catalog().T("attachment.filename", reportData.Metadata.Business,
+		reportData.Metadata.GeneratedAt.Format("2006-01-02"))
+	attachments := make([]mail.Attachment, 0, len(formats))
+	for _, format := range formats {
+		renderer, err := i.reportGenerator.Renderer(report.Format(format))
+		if err != nil {
+			return nil, err
+		}
+
+		// HTML报告已生成，直接复用
+		data := []byte(htmlReport)
+		if format != devopsv1.ReportFormatHTML {
+			data, err = renderer.Render(reportData)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		attachments = append(attachments, mail.Attachment{
+			Filename:    filename + "." + renderer.Extension(),
+			ContentType: renderer.ContentType(),
+			Data:        data,
+		})
+	}
+
+	return attachments, nil
+}
+
+// sendWebhook 按渠道配置的格式渲染报告并发送
+func (i *Inspector) sendWebhook(ctx context.Context, hook devopsv1.Webhook, reportData *report.ReportData) (err error) {
+	format := report.Format(hook.Format)
+	if format == "" {
+		format = report.FormatJSON
+	}
+
+	ctx, span := tracing.Start(ctx, "Notify", tracing.AttrChannel.String(metrics.ChannelWebhook),
+		tracing.AttrWebhook.String(hook.Name), tracing.AttrFormat.String(string(format)))
+	defer func() { tracing.End(span, err) }()
+
+	renderer, err := i.reportGenerator.Renderer(format)
+	if err != nil {
+		return err
+	}
+
+	body, err := renderer.Render(reportData)
+	if err != nil {
+		return fmt.Errorf("生成%s报告失败: %w", format, err)
+	}
+
+	return i.webhookSender.Send(ctx, hook.URL, renderer.ContentType(), body)
+}
+
+// defaultStaleAfter 未配置时判定数据过期的抓取间隔
+const defaultStaleAfter = 3 * time.Minute
+
+// queryReason 返回报告中指标采集失败的原因，查询结果无法解析时与查询失败区分
+func queryReason(catalog *i18n.Catalog, err error) string {
+	if errors.Is(err, prometheus.ErrNoResults) || errors.Is(err, prometheus.ErrNonFinite) ||
+		errors.Is(err, prometheus.ErrMultipleSeries) {
+		return catalog.T("reason.parseFailed", queryCause(err))
+	}
+	return catalog.T("reason.queryFailed", queryCause(err))
+}
+
+// queryCause 返回指标采集失败的原因，去掉collectNodeMetrics添加的查询说明，只保留数据源返回的错误，
+// 报告中的原因使用巡检配置的语言
+func queryCause(err error) error {
+	if cause := errors.Unwrap(err); cause != nil {
+		return cause
+	}
+	return err
+}
+
+// checkNodeHealth 检查主机的up状态和最近一次抓取距今的时长，同时返回up指标上的Prometheus目标标签
+func (i *Inspector) checkNodeHealth(
+	ctx context.Context,
+	client *prometheus.Failover,
+	node string,
+	matchers []prometheus.Matcher,
+	now time.Time,
+) (report.NodeHealth, map[string]string) {
+	catalog := i18n.NewCatalog(i.inspection.Spec.Language)
+
+	upResult, err := client.Query(ctx, prometheus.NodeUpQuery(node, matchers), now)
+	if err != nil {
+		return report.NodeHealth{State: report.NodeStateUnknown, Reason: catalog.T("reason.queryFailed", err)}, nil
+	}
+	up, err := prometheus.ParseVector(upResult)
+	if err != nil {
+		return report.NodeHealth{State: report.NodeStateUnknown, Reason: catalog.T("reason.parseFailed", err)}, nil
+	}
+	if len(up) == 0 {
+		return report.NodeHealth{State: report.NodeStateStale, Reason: catalog.T("reason.noData")}, nil
+	}
+
+	// 同一主机有多个抓取任务时合并各任务的标签
+	targetLabels := make(map[string]string)
+	for _, sample := range up {
+		for key, value := range sample.Metric {
+			if key != "__name__" {
+				targetLabels[key] = value
+			}
+		}
+	}
+
+	health := report.NodeHealth{State: report.NodeStateOK}
+
+	// 抓取时长查询失败不影响巡检，只是无法判断数据是否过期
+	ageResult, err := client.Query(ctx, prometheus.ScrapeAgeQuery(node, matchers), now)
+	if err == nil {
+		if ages, err := prometheus.ParseVector(ageResult); err == nil {
+			for _, age := range ages {
+				if d := time.Duration(age.Value * float64(time.Second)); d > health.ScrapeAge {
+					health.ScrapeAge = d.Round(time.Second)
+				}
+			}
+		}
+	}
+
+	// 同一主机有多个抓取任务时，任一任务抓取失败即视为宕机
+	for _, sample := range up {
+		if sample.Value == 0 {
+			health.State = report.NodeStateDown
+			health.Reason = catalog.T("reason.down")
+			return health, targetLabels
+		}
+	}
+
+	staleAfter := defaultStaleAfter
+	if i.inspection.Spec.StaleAfter != nil {
+		staleAfter = i.inspection.Spec.StaleAfter.Duration
+	}
+	if health.ScrapeAge > staleAfter {
+		health.State = report.NodeStateStale
+		health.Reason = catalog.T("reason.stale", health.ScrapeAge, staleAfter)
+	}
+
+	return health, targetLabels
+}
+
+// comparisons 返回配置的对比偏移，未配置时只与24小时前对比
+func (i *Inspector) comparisons() ([]report.Comparison, error) {
+	specs := i.inspection.Spec.Comparisons
+	if len(specs) == 0 {
+		return []report.Comparison{report.DefaultComparison}, nil
+	}
+
+	comparisons := make([]report.Comparison, 0, len(specs))
+	for _, spec := range specs {
+		duration, err := report.ParseOffset(spec.Offset)
+		if err != nil {
+			return nil, err
+		}
+		comparison := report.Comparison{
+			Offset:        spec.Offset,
+			Duration:      duration,
+			RateThreshold: float64(spec.RateThreshold),
+		}
+		if comparison.RateThreshold <= 0 {
+			comparison.RateThreshold = report.RateThreshold
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons, nil
+}
+
+// thresholds 返回配置的阈值规则
+func (i *Inspector) thresholds() report.Thresholds {
+	rules := make([]report.DeltaRule, 0, len(i.inspection.Spec.DeltaRules))
+	for _, rule := range i.inspection.Spec.DeltaRules {
+		rules = append(rules, deltaRule(rule))
+	}
+	return report.NewThresholds(rules)
+}
+
+// silences 返回配置的静默规则
+func (i *Inspector) silences() []report.Silence {
+	silences := make([]report.Silence, 0, len(i.inspection.Spec.Silences))
+	for _, silence := range i.inspection.Spec.Silences {
+		silences = append(silences, report.Silence{
+			Node:      silence.Node,
+			Selector:  silence.Selector,
+			Metric:    silence.Metric,
+			Reason:    silence.Reason,
+			Owner:     silence.Owner,
+			ExpiresAt: silence.ExpiresAt.Time,
+		})
+	}
+	return silences
+}
+
+// deltaRule 将CRD中的差值规则转换为报告使用的规则
+func deltaRule(rule devopsv1.DeltaRule) report.DeltaRule {
+	return report.DeltaRule{
+		Metric:      rule.Metric,
+		Mode:        rule.Mode,
+		Direction:   rule.Direction,
+		Threshold:   float64(rule.Threshold),
+		MinBaseline: float64(rule.MinBaseline),
+	}
+}
+
+// thresholdOverrides 返回配置的阈值覆盖规则和主机分组
+func (i *Inspector) thresholdOverrides() ([]report.ThresholdOverride, []report.NodeGroup, error) {
+	specGroups := i.inspection.Spec.InspectionObject.Groups
+	groups := make([]report.NodeGroup, 0, len(specGroups))
+	names := make(map[string]bool, len(specGroups))
+	for _, group := range specGroups {
+		if names[group.Name] {
+			return nil, nil, fmt.Errorf("主机分组%q重复定义", group.Name)
+		}
+		names[group.Name] = true
+		groups = append(groups, report.NodeGroup{
+			Name:   group.Name,
+			Nodes:  group.Nodes,
+			Labels: group.Labels,
+		})
+	}
+
+	specs := i.inspection.Spec.ThresholdOverrides
+	overrides := make([]report.ThresholdOverride, 0, len(specs))
+	for _, spec := range specs {
+		override := report.ThresholdOverride{
+			Node:     spec.Node,
+			Selector: spec.Selector,
+			Group:    spec.Group,
+			Disk:     float64(spec.Disk),
+			Inode:    float64(spec.Inode),
+			CPU:      float64(spec.CPU),
+			Memory:   float64(spec.Memory),
+		}
+		for _, rule := range spec.DeltaRules {
+			override.Deltas = append(override.Deltas, deltaRule(rule))
+		}
+		if err := override.Validate(); err != nil {
+			return nil, nil, err
+		}
+		if override.Group != "" && !names[override.Group] {
+			return nil, nil, fmt.Errorf("阈值覆盖引用的分组%q未在inspectionObject.groups中定义", override.Group)
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, groups, nil
+}
+
+// collectOffsets 收集节点与各额外对比偏移相比的指标，硬盘和inode与主对比一致按分区查询，
+// 对比时间点不存在的分区以当前值作为对比值。
+// 某个偏移查询失败（如超出Prometheus数据保留时长）时只影响该偏移的对比列。
+func (i *Inspector) collectOffsets(
+	ctx context.Context,
+	client *prometheus.Failover,
+	node string,
+	matchers []prometheus.Matcher,
+	now time.Time,
+	current *report.NodeMetric,
+	comparisons []report.Comparison,
+) []report.OffsetMetric {
+	logger := log.FromContext(ctx)
+
+	filter := i.filesystemFilter()
+	diskQuery := prometheus.FilesystemUsageQuery(node, matchers, filter)
+	inodeQuery := prometheus.FilesystemInodeQuery(node, matchers, filter)
+	queries := []string{
+		prometheus.CPUUsageQuery(node, matchers),
+		prometheus.MemoryUsageQuery(node, matchers),
+	}
+
+	offsets := make([]report.OffsetMetric, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		offset := report.OffsetMetric{Offset: comparison.Offset}
+		ts := now.Add(-comparison.Duration)
+
+		values := make([]float64, 0, len(queries))
+		for _, query := range queries {
+			result, err := client.Query(ctx, query, ts)
+			if err != nil {
+				logger.Error(err, "查询对比值失败", "node", node, "offset", comparison.Offset)
+				break
+			}
+			value, err := prometheus.ParseValue(result)
+			if err != nil {
+				logger.Error(err, "解析对比值失败", "node", node, "offset", comparison.Offset)
+				break
+			}
+			values = append(values, math.Round(value*100)/100)
+		}
+		if len(values) < len(queries) {
+			offsets = append(offsets, offset)
+			continue
+		}
+
+		disk, err := i.queryFilesystems(ctx, client, diskQuery, ts)
+		if err != nil {
+			logger.Error(err, "查询对比时间点的硬盘使用率失败", "node", node, "offset", comparison.Offset)
+			offsets = append(offsets, offset)
+			continue
+		}
+		inode, err := i.queryFilesystems(ctx, client, inodeQuery, ts)
+		if err != nil {
+			logger.Error(err, "查询对比时间点的inode使用率失败", "node", node, "offset", comparison.Offset)
+			offsets = append(offsets, offset)
+			continue
+		}
+
+		offset.Valid = true
+		offset.CPUOffset, offset.MemOffset = values[0], values[1]
+		offset.CPURate = math.Round((current.CPUNow-offset.CPUOffset)*100) / 100
+		offset.MemRate = math.Round((current.MemNow-offset.MemOffset)*100) / 100
+		offset.Filesystems = filesystemOffsets(current.Filesystems, disk, inode)
+		for _, fs := range offset.Filesystems {
+			offset.DiskOffset = math.Max(offset.DiskOffset, fs.DiskOffset)
+			offset.InodeOffset = math.Max(offset.InodeOffset, fs.InodeOffset)
+		}
+		offset.DiskRate = math.Round((current.DiskNow-offset.DiskOffset)*100) / 100
+		offset.InodeRate = math.Round((current.InodeNow-offset.InodeOffset)*100) / 100
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+// offsetValue 返回分区在对比时间点的使用率，对比时间点不存在的分区（如新挂载的分区）以当前值作为对比值
+func offsetValue(samples map[string]prometheus.VectorSample, key string, current float64) float64 {
+	if sample, ok := samples[key]; ok {
+		return math.Round(sample.Value*100) / 100
+	}
+	return current
+}
+
+// filesystemOffsets 按当前的分区计算与对比时间点相比的硬盘和inode使用率
+func filesystemOffsets(filesystems []report.FilesystemMetric, disk, inode map[string]prometheus.VectorSample) []report.FilesystemOffset {
+	result := make([]report.FilesystemOffset, 0, len(filesystems))
+	for _, fs := range filesystems {
+		key := filesystemKey(map[string]string{"device": fs.Device, "mountpoint": fs.Mountpoint})
+		item := report.FilesystemOffset{
+			Device:      fs.Device,
+			Mountpoint:  fs.Mountpoint,
+			DiskOffset:  offsetValue(disk, key, fs.DiskNow),
+			InodeOffset: offsetValue(inode, key, fs.InodeNow),
+		}
+		item.DiskRate = math.Round((fs.DiskNow-item.DiskOffset)*100) / 100
+		item.InodeRate = math.Round((fs.InodeNow-item.InodeOffset)*100) / 100
+		result = append(result, item)
+	}
+	return result
+}
+
+// filesystemFilter 返回文件系统过滤条件
+func (i *Inspector) filesystemFilter() prometheus.FilesystemFilter {
+	spec := i.inspection.Spec.Filesystems
+	return prometheus.FilesystemFilter{
+		IncludeFSTypes:     spec.IncludeFSTypes,
+		ExcludeFSTypes:     spec.ExcludeFSTypes,
+		IncludeMountpoints: spec.IncludeMountpoints,
+		ExcludeMountpoints: spec.ExcludeMountpoints,
+	}
+}
+
+// filesystemThresholds 返回按挂载点覆盖的阈值规则
+func (i *Inspector) filesystemThresholds() []report.FilesystemThreshold {
+	rules := make([]report.FilesystemThreshold, 0, len(i.inspection.Spec.Filesystems.Thresholds))
+	for _, rule := range i.inspection.Spec.Filesystems.Thresholds {
+		rules = append(rules, report.FilesystemThreshold{
+			Mountpoint: rule.Mountpoint,
+			Disk:       float64(rule.Disk),
+			Inode:      float64(rule.Inode),
+		})
+	}
+	return rules
+}
+
+// filesystemKey 以设备和挂载点标识一个分区
+func filesystemKey(metric map[string]string) string {
+	return metric["device"] + " " + metric["mountpoint"]
+}
+
+// queryFilesystems 执行分区查询，返回按分区索引的结果
+func (i *Inspector) queryFilesystems(ctx context.Context, client *prometheus.Failover, query string, ts time.Time) (map[string]prometheus.VectorSample, error) {
+	result, err := client.Query(ctx, query, ts)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := prometheus.ParseVector(result)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := make(map[string]prometheus.VectorSample, len(samples))
+	for _, sample := range samples {
+		indexed[filesystemKey(sample.Metric)] = sample
+	}
+	return indexed, nil
+}
+
+// collectFilesystems 收集节点各分区的硬盘和inode使用率，并设置各分区生效的阈值
+func (i *Inspector) collectFilesystems(
+	ctx context.Context,
+	client *prometheus.Failover,
+	node string,
+	matchers []prometheus.Matcher,
+	now, offsetTime time.Time,
+) ([]report.FilesystemMetric, error) {
+	filter := i.filesystemFilter()
+	diskQuery := prometheus.FilesystemUsageQuery(node, matchers, filter)
+	inodeQuery := prometheus.FilesystemInodeQuery(node, matchers, filter)
+
+	diskNow, err := i.queryFilesystems(ctx, client, diskQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("查询硬盘使用率失败: %w", err)
+	}
+	if len(diskNow) == 0 {
+		return nil, fmt.Errorf("解析硬盘使用率失败: %w", prometheus.ErrNoResults)
+	}
+	diskOffset, err := i.queryFilesystems(ctx, client, diskQuery, offsetTime)
+	if err != nil {
+		return nil, fmt.Errorf("查询对比时间点的硬盘使用率失败: %w", err)
+	}
+	inodeNow, err := i.queryFilesystems(ctx, client, inodeQuery, now)
+	if err != nil {
+		return nil, fmt.Errorf("查询inode使用率失败: %w", err)
+	}
+	inodeOffset, err := i.queryFilesystems(ctx, client, inodeQuery, offsetTime)
+	if err != nil {
+		return nil, fmt.Errorf("查询对比时间点的inode使用率失败: %w", err)
+	}
+
+	filesystems := make([]report.FilesystemMetric, 0, len(diskNow))
+	for key, disk := range diskNow {
+		fs := report.FilesystemMetric{
+			Device:     disk.Metric["device"],
+			Mountpoint: disk.Metric["mountpoint"],
+			FSType:     disk.Metric["fstype"],
+			DiskNow:    math.Round(disk.Value*100) / 100,
+		}
+		fs.DiskOffset = offsetValue(diskOffset, key, fs.DiskNow)
+		fs.DiskRate = math.Round((fs.DiskNow-fs.DiskOffset)*100) / 100
+		if inode, ok := inodeNow[key]; ok {
+			fs.InodeNow = math.Round(inode.Value*100) / 100
+			fs.InodeOffset = offsetValue(inodeOffset, key, fs.InodeNow)
+			fs.InodeRate = math.Round((fs.InodeNow-fs.InodeOffset)*100) / 100
+		}
+		filesystems = append(filesystems, fs)
+	}
+	sort.Slice(filesystems, func(a, b int) bool {
+		return filesystems[a].Mountpoint < filesystems[b].Mountpoint
+	})
+
+	if err := report.ApplyFilesystemThresholds(filesystems, i.filesystemThresholds()); err != nil {
+		return nil, err
+	}
+	return filesystems, nil
+}
+
+// forecastConfig 返回写满预测配置，未配置时返回nil
+func (i *Inspector) forecastConfig() *report.ForecastConfig {
+	spec := i.inspection.Spec.Forecast
+	if spec == nil {
+		return nil
+	}
+
+	config := &report.ForecastConfig{
+		Window:        spec.Window,
+		ThresholdDays: float64(spec.ThresholdDays),
+	}
+	if config.Window == "" {
+		config.Window = report.DefaultForecastWindow
+	}
+	if config.ThresholdDays <= 0 {
+		config.ThresholdDays = report.ForecastThreshold
+	}
+	return config
+}
+
+// collectDiskForecast 预测节点磁盘写满时间，返回最先写满的分区，没有分区在增长时返回nil
+func (i *Inspector) collectDiskForecast(
+	ctx context.Context,
+	client *prometheus.Failover,
+	node string,
+	matchers []prometheus.Matcher,
+	now time.Time,
+	window string,
+) *report.DiskForecast {
+	logger := log.FromContext(ctx)
+
+	result, err := client.Query(ctx, prometheus.DiskFullForecastQuery(node, matchers, i.filesystemFilter(), window), now)
+	if err != nil {
+		logger.Error(err, "查询磁盘写满预测失败", "node", node)
+		return nil
+	}
+	samples, err := prometheus.ParseVector(result)
+	if err != nil {
+		logger.Error(err, "解析磁盘写满预测失败", "node", node)
+		return nil
+	}
+
+	var forecast *report.DiskForecast
+	for _, sample := range samples {
+		if forecast == nil || sample.Value < forecast.Days {
+			forecast = &report.DiskForecast{
+				Mountpoint: sample.Metric["mountpoint"],
+				Days:       math.Round(sample.Value*100) / 100,
+			}
+		}
+	}
+	return forecast
+}
+
+// trendStep 趋势图的采样间隔
+const trendStep = 15 * time.Minute
+
+// collectNodeTrends 通过范围查询收集节点各项指标在[start, end]内的趋势，硬盘和inode为各分区使用率的最大值
+func (i *Inspector) collectNodeTrends(
+	ctx context.Context,
+	client *prometheus.Failover,
+	node string,
+	matchers []prometheus.Matcher,
+	start, end time.Time,
+) map[string][]report.Point {
+	logger := log.FromContext(ctx)
+
+	queries := i.usageQueries(node, matchers)
+	trends := make(map[string][]report.Point, len(queries))
+	for _, metric := range report.TrendMetrics {
+		result, err := client.QueryRange(ctx, queries[metric], start, end, trendStep)
+		if err != nil {
+			logger.Error(err, "查询趋势失败", "node", node, "metric", metric)
+			continue
+		}
+		samples, err := prometheus.ParseSeries(result)
+		if err != nil {
+			logger.Error(err, "解析趋势失败", "node", node, "metric", metric)
+			continue
+		}
+
+		points := make([]report.Point, 0, len(samples))
+		for _, sample := range samples {
+			points = append(points, report.Point{
+				Time:  sample.Time,
+				Value: math.Round(sample.Value*100) / 100,
+			})
+		}
+		trends[metric] = points
+	}
+
+	return trends
+}
+
+// usageQueries 返回节点各项指标使用率的查询，硬盘和inode取使用率最大的分区
+func (i *Inspector) usageQueries(node string, matchers []prometheus.Matcher) map[string]string {
+	return map[string]string{
+		report.MetricDisk:   prometheus.DiskUsageQuery(node, matchers, i.filesystemFilter()),
+		report.MetricInode:  prometheus.InodeUsageQuery(node, matchers, i.filesystemFilter()),
+		report.MetricCPU:    prometheus.CPUUsageQuery(node, matchers),
+		report.MetricMemory: prometheus.MemoryUsageQuery(node, matchers),
+	}
+}
+
+// baselineConfig 返回动态基线配置，未配置时返回nil
+func (i *Inspector) baselineConfig() (*report.BaselineConfig, error) {
+	spec := i.inspection.Spec.Baseline
+	if spec == nil {
+		return nil, nil
+	}
+
+	config := &report.BaselineConfig{
+		Days:  int(spec.Days),
+		Sigma: float64(spec.Sigma),
+		Step:  report.DefaultBaselineStep,
+	}
+	if config.Days <= 0 {
+		config.Days = report.DefaultBaselineDays
+	}
+	if config.Sigma <= 0 {
+		config.Sigma = report.DefaultBaselineSigma
+	}
+	if spec.Step != "" {
+		step, err := report.ParseOffset(spec.Step)
+		if err != nil {
+			return nil, fmt.Errorf("基线采样间隔无效: %w", err)
+		}
+		config.Step = step
+	}
+	return config, nil
+}
+
+// collectBaselines 通过范围查询节点各项指标在最近几天的历史数据，计算均值和标准差。
+// 窗口截止到上一个采样点，不包含当前值
+func (i *Inspector) collectBaselines(
+	ctx context.Context,
+	client *prometheus.Failover,
+	node string,
+	matchers []prometheus.Matcher,
+	now time.Time,
+	config *report.BaselineConfig,
+) map[string]report.Baseline {
+	logger := log.FromContext(ctx)
+
+	start := now.Add(-time.Duration(config.Days) * 24 * time.Hour)
+	end := now.Add(-config.Step)
+	queries := i.usageQueries(node, matchers)
+	baselines := make(map[string]report.Baseline, len(queries))
+	for _, metric := range report.TrendMetrics {
+		result, err := client.QueryRange(ctx, queries[metric], start, end, config.Step)
+		if err != nil {
+			logger.Error(err, "查询基线历史数据失败", "node", node, "metric", metric)
+			continue
+		}
+		samples, err := prometheus.ParseSeries(result)
+		if err != nil {
+			logger.Error(err, "解析基线历史数据失败", "node", node, "metric", metric)
+			continue
+		}
+
+		values := make([]float64, 0, len(samples))
+		for _, sample := range samples {
+			values = append(values, sample.Value)
+		}
+		baselines[metric] = report.NewBaseline(values)
+	}
+	return baselines
+}
+
 // collectNodeMetrics 收集节点指标
 func (i *Inspector) collectNodeMetrics(
 	ctx context.Context,
+	client *prometheus.Failover,
 	node string,
-	labels map[string]string,
-	now, dayAgo time.Time,
+	matchers []prometheus.Matcher,
+	now, offsetTime time.Time,
 ) (*report.NodeMetric, error) {
 	metrics := &report.NodeMetric{
 		Name: node,
 	}
 
 	// 采集CPU使用率
-	cpuQuery := prometheus.CPUUsageQuery(node, labels)
-	cpuResult, err := i.prometheusClient.Query(ctx, cpuQuery, now)
+	cpuQuery := prometheus.CPUUsageQuery(node, matchers)
+	cpuResult, err := client.Query(ctx, cpuQuery, now)
 	if err != nil {
 		return nil, fmt.Errorf("查询CPU使用率失败: %w", err)
 	}
@@ -135,21 +1065,21 @@ func (i *Inspector) collectNodeMetrics(
 	}
 	metrics.CPUNow = math.Round(cpuValue*100) / 100
 
-	// 采集24小时前的CPU使用率
-	cpuOffsetResult, err := i.prometheusClient.Query(ctx, cpuQuery, dayAgo)
+	// 采集对比时间点的CPU使用率
+	cpuOffsetResult, err := client.Query(ctx, cpuQuery, offsetTime)
 	if err != nil {
-		return nil, fmt.Errorf("查询24小时前CPU使用率失败: %w", err)
+		return nil, fmt.Errorf("查询对比时间点的CPU使用率失败: %w", err)
 	}
 	cpuOffsetValue, err := prometheus.ParseValue(cpuOffsetResult)
 	if err != nil {
-		return nil, fmt.Errorf("解析24小时前CPU使用率失败: %w", err)
+		return nil, fmt.Errorf("解析对比时间点的CPU使用率失败: %w", err)
 	}
 	metrics.CPUOffset = math.Round(cpuOffsetValue*100) / 100
 	metrics.CPURate = math.Round((metrics.CPUNow-metrics.CPUOffset)*100) / 100
 
 	// 采集内存使用率
-	memoryQuery := prometheus.MemoryUsageQuery(node, labels)
-	memoryResult, err := i.prometheusClient.Query(ctx, memoryQuery, now)
+	memoryQuery := prometheus.MemoryUsageQuery(node, matchers)
+	memoryResult, err := client.Query(ctx, memoryQuery, now)
 	if err != nil {
 		return nil, fmt.Errorf("查询内存使用率失败: %w", err)
 	}
@@ -159,64 +1089,31 @@ func (i *Inspector) collectNodeMetrics(
 	}
 	metrics.MemNow = math.Round(memoryValue*100) / 100
 
-	// 采集24小时前的内存使用率
-	memoryOffsetResult, err := i.prometheusClient.Query(ctx, memoryQuery, dayAgo)
+	// 采集对比时间点的内存使用率
+	memoryOffsetResult, err := client.Query(ctx, memoryQuery, offsetTime)
 	if err != nil {
-		return nil, fmt.Errorf("查询24小时前内存使用率失败: %w", err)
+		return nil, fmt.Errorf("查询对比时间点的内存使用率失败: %w", err)
 	}
 	memoryOffsetValue, err := prometheus.ParseValue(memoryOffsetResult)
 	if err != nil {
-		return nil, fmt.Errorf("解析24小时前内存使用率失败: %w", err)
+		return nil, fmt.Errorf("解析对比时间点的内存使用率失败: %w", err)
 	}
 	metrics.MemOffset = math.Round(memoryOffsetValue*100) / 100
 	metrics.MemRate = math.Round((metrics.MemNow-metrics.MemOffset)*100) / 100
 
-	// 采集硬盘使用率 (取使用率最大的分区)
-	diskQuery := prometheus.DiskUsageQuery(node, labels)
-	diskResult, err := i.prometheusClient.Query(ctx, diskQuery, now)
-	if err != nil {
-		return nil, fmt.Errorf("查询硬盘使用率失败: %w", err)
-	}
-	diskValue, err := prometheus.ParseValue(diskResult)
-	if err != nil {
-		return nil, fmt.Errorf("解析硬盘使用率失败: %w", err)
-	}
-	metrics.DiskNow = math.Round(diskValue*100) / 100
-
-	// 采集24小时前的硬盘使用率
-	diskOffsetResult, err := i.prometheusClient.Query(ctx, diskQuery, dayAgo)
+	// 采集各分区的硬盘和inode使用率，主机的汇总值取使用率最大的分区
+	filesystems, err := i.collectFilesystems(ctx, client, node, matchers, now, offsetTime)
 	if err != nil {
-		return nil, fmt.Errorf("查询24小时前硬盘使用率失败: %w", err)
+		return nil, err
 	}
-	diskOffsetValue, err := prometheus.ParseValue(diskOffsetResult)
-	if err != nil {
-		return nil, fmt.Errorf("解析24小时前硬盘使用率失败: %w", err)
+	metrics.Filesystems = filesystems
+	for _, fs := range filesystems {
+		metrics.DiskNow = math.Max(metrics.DiskNow, fs.DiskNow)
+		metrics.DiskOffset = math.Max(metrics.DiskOffset, fs.DiskOffset)
+		metrics.InodeNow = math.Max(metrics.InodeNow, fs.InodeNow)
+		metrics.InodeOffset = math.Max(metrics.InodeOffset, fs.InodeOffset)
 	}
-	metrics.DiskOffset = math.Round(diskOffsetValue*100) / 100
 	metrics.DiskRate = math.Round((metrics.DiskNow-metrics.DiskOffset)*100) / 100
-
-	// 采集inode使用率
-	inodeQuery := prometheus.InodeUsageQuery(node, labels)
-	inodeResult, err := i.prometheusClient.Query(ctx, inodeQuery, now)
-	if err != nil {
-		return nil, fmt.Errorf("查询inode使用率失败: %w", err)
-	}
-	inodeValue, err := prometheus.ParseValue(inodeResult)
-	if err != nil {
-		return nil, fmt.Errorf("解析inode使用率失败: %w", err)
-	}
-	metrics.InodeNow = math.Round(inodeValue*100) / 100
-
-	// 采集24小时前的inode使用率
-	inodeOffsetResult, err := i.prometheusClient.Query(ctx, inodeQuery, dayAgo)
-	if err != nil {
-		return nil, fmt.Errorf("查询24小时前inode使用率失败: %w", err)
-	}
-	inodeOffsetValue, err := prometheus.ParseValue(inodeOffsetResult)
-	if err != nil {
-		return nil, fmt.Errorf("解析24小时前inode使用率失败: %w", err)
-	}
-	metrics.InodeOffset = math.Round(inodeOffsetValue*100) / 100
 	metrics.InodeRate = math.Round((metrics.InodeNow-metrics.InodeOffset)*100) / 100
 
 	return metrics, nil
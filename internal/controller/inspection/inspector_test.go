@@ -0,0 +1,60 @@
+package inspection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/prometheus"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/report"
+)
+
+var _ = Describe("Inspector", func() {
+	Describe("checkNodeHealth", func() {
+		DescribeTable("should report query failures in the configured language",
+			func(language, prefix string) {
+				inspector := newTestInspector(devopsv1.DataSource{Name: "default", URL: fakePrometheus(http.StatusBadRequest)})
+				inspector.inspection = &devopsv1.AutoInspection{Spec: devopsv1.AutoInspectionSpec{Language: language}}
+
+				health, _ := inspector.checkNodeHealth(context.Background(), inspector.failoverFor("sh-1", nil), "sh-1", nil, time.Now())
+				Expect(health.State).To(Equal(report.NodeStateUnknown))
+				Expect(health.Reason).To(HavePrefix(prefix))
+			},
+			Entry("in Chinese", "zh-CN", "查询指标失败: "),
+			Entry("in English", "en-US", "failed to query metrics: "),
+		)
+	})
+
+	Describe("queryCause", func() {
+		It("should drop the description added while collecting metrics", func() {
+			cause := errors.New("unexpected status code: 400")
+			Expect(queryCause(fmt.Errorf("查询CPU使用率失败: %w", cause))).To(Equal(cause))
+		})
+
+		It("should keep errors without a cause", func() {
+			err := errors.New("unexpected status code: 400")
+			Expect(queryCause(err)).To(Equal(err))
+		})
+	})
+
+	Describe("queryReason", func() {
+		DescribeTable("should localize the reason of collection failures",
+			func(language string, err error, reason string) {
+				Expect(queryReason(i18n.NewCatalog(language), err)).To(Equal(reason))
+			},
+			Entry("query failure", "en-US", fmt.Errorf("查询CPU使用率失败: %w", errors.New("unexpected status code: 400")),
+				"failed to query metrics: unexpected status code: 400"),
+			Entry("missing filesystem metrics", "en-US", fmt.Errorf("解析硬盘使用率失败: %w", prometheus.ErrNoResults),
+				"failed to parse metrics: no results returned"),
+			Entry("missing filesystem metrics in Chinese", "zh-CN", fmt.Errorf("解析硬盘使用率失败: %w", prometheus.ErrNoResults),
+				"解析指标失败: no results returned"),
+		)
+	})
+})
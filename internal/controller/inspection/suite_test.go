@@ -0,0 +1,14 @@
+package inspection
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestInspection(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Inspection Suite")
+}
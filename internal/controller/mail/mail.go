@@ -1,12 +1,19 @@
 package mail
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 
 	devopsv1 "github.com/rxg456/auto-inspection-operator/api/v1"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -22,23 +29,32 @@ func NewSender(config devopsv1.SMTP) *Sender {
 	}
 }
 
-// SendMail 发送邮件
-func (s *Sender) SendMail(to []string, subject, body string) error {
-	logger := log.Log.WithName("mail-sender")
+// Attachment 邮件附件
+type Attachment struct {
+	// 附件文件名
+	Filename string
+	// 附件MIME类型
+	ContentType string
+	// 附件内容
+	Data []byte
+}
 
-	// 设置邮件头
-	header := make(map[string]string)
-	header["From"] = s.Config.From
-	header["To"] = strings.Join(to, ";")
-	header["Subject"] = subject
-	header["Content-Type"] = "text/html; charset=UTF-8"
+// SendMail 发送邮件，可附带任意数量的附件，并记录发送结果
+func (s *Sender) SendMail(to []string, subject, body string, attachments ...Attachment) error {
+	err := s.send(to, subject, body, attachments)
+	metrics.ObserveNotification(metrics.ChannelMail, err)
+	return err
+}
+
+// send 按端口选择连接方式发送邮件
+func (s *Sender) send(to []string, subject, body string, attachments []Attachment) error {
+	logger := log.Log.WithName("mail-sender")
 
 	// 组装邮件内容
-	message := ""
-	for k, v := range header {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
+	message, err := buildMessage(s.Config.From, to, subject, body, attachments)
+	if err != nil {
+		return fmt.Errorf("组装邮件内容失败: %w", err)
 	}
-	message += "\r\n" + body
 
 	// 邮件服务器地址
 	addr := fmt.Sprintf("%s:%d", s.Config.Server, s.Config.Port)
@@ -48,10 +64,10 @@ func (s *Sender) SendMail(to []string, subject, body string) error {
 	switch s.Config.Port {
 	case 465:
 		// SSL 连接方式
-		return s.sendMailWithSSL(addr, to, []byte(message))
+		return s.sendMailWithSSL(addr, to, message)
 	case 587:
 		// TLS 连接方式
-		return s.sendMailWithTLS(addr, to, []byte(message))
+		return s.sendMailWithTLS(addr, to, message)
 	default:
 		// 标准连接方式
 		// 认证信息
@@ -59,7 +75,7 @@ func (s *Sender) SendMail(to []string, subject, body string) error {
 		logger.Info("使用标准SMTP连接发送邮件")
 
 		// 发送邮件
-		err := smtp.SendMail(addr, auth, s.Config.From, to, []byte(message))
+		err := smtp.SendMail(addr, auth, s.Config.From, to, message)
 		if err != nil {
 			logger.Error(err, "标准SMTP连接发送邮件失败")
 			return fmt.Errorf("标准SMTP连接发送邮件失败: %w", err)
@@ -193,3 +209,79 @@ func (s *Sender) sendMailWithTLS(addr string, to []string, msg []byte) error {
 
 	return nil
 }
+
+// buildMessage 组装邮件内容，有附件时使用multipart/mixed格式
+func buildMessage(from string, to []string, subject, body string, attachments []Attachment) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// 设置邮件头
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ";"))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(attachments) == 0 {
+		buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+		buf.WriteString("\r\n" + body)
+		return buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", writer.Boundary())
+
+	// 邮件正文
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建邮件正文失败: %w", err)
+	}
+	if err := writeBase64(part, []byte(body)); err != nil {
+		return nil, fmt.Errorf("写入邮件正文失败: %w", err)
+	}
+
+	// 邮件附件
+	for _, attachment := range attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachmentContentType(attachment)},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("创建附件%s失败: %w", attachment.Filename, err)
+		}
+		if err := writeBase64(part, attachment.Data); err != nil {
+			return nil, fmt.Errorf("写入附件%s失败: %w", attachment.Filename, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("结束邮件内容失败: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// attachmentContentType 返回附件的Content-Type，保留原有参数如charset并添加文件名，
+// 未指定或无法解析时使用application/octet-stream
+func attachmentContentType(attachment Attachment) string {
+	mediaType, params, err := mime.ParseMediaType(attachment.ContentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+	params["name"] = attachment.Filename
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// writeBase64 按RFC 2045要求每76个字符换行写入base64内容
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+	return err
+}
@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// failingWriter 写入指定字节数后返回错误
+type failingWriter struct {
+	remaining int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.remaining {
+		n := w.remaining
+		w.remaining = 0
+		return n, errors.New("disk full")
+	}
+	w.remaining -= len(p)
+	return len(p), nil
+}
+
+// decodePart 读取并解码base64编码的部件内容
+func decodePart(part *multipart.Part) []byte {
+	ExpectWithOffset(1, part.Header.Get("Content-Transfer-Encoding")).To(Equal("base64"))
+	encoded, err := io.ReadAll(part)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	for _, line := range strings.Split(strings.TrimRight(string(encoded), "\r\n"), "\r\n") {
+		ExpectWithOffset(1, len(line)).To(BeNumerically("<=", 76))
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return decoded
+}
+
+var _ = Describe("buildMessage", func() {
+	It("should send the body as HTML without attachments", func() {
+		message, err := buildMessage("ops@example.com", []string{"a@example.com", "b@example.com"}, "巡检报告", "<p>正常</p>", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed, err := mail.ReadMessage(bytes.NewReader(message))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Header.Get("From")).To(Equal("ops@example.com"))
+		Expect(parsed.Header.Get("To")).To(Equal("a@example.com;b@example.com"))
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subject).To(Equal("巡检报告"))
+		Expect(parsed.Header.Get("Content-Type")).To(Equal("text/html; charset=UTF-8"))
+
+		body, err := io.ReadAll(parsed.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal("<p>正常</p>"))
+	})
+
+	It("should round-trip the body and attachments through base64", func() {
+		body := "<p>" + strings.Repeat("巡检结果", 50) + "</p>"
+		attachments := []Attachment{
+			{Filename: "report.csv", ContentType: "text/csv; charset=utf-8", Data: []byte("\ufeff主机IP,状态\n192.168.0.1:9100,正常\n")},
+			{Filename: "巡检报告.xlsx", Data: bytes.Repeat([]byte{0x50, 0x4b, 0x03, 0x04, 0x00, 0xff}, 100)},
+		}
+		message, err := buildMessage("ops@example.com", []string{"a@example.com"}, "巡检报告", body, attachments)
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed, err := mail.ReadMessage(bytes.NewReader(message))
+		Expect(err).NotTo(HaveOccurred())
+		mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mediaType).To(Equal("multipart/mixed"))
+
+		reader := multipart.NewReader(parsed.Body, params["boundary"])
+		part, err := reader.NextPart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(part.Header.Get("Content-Type")).To(Equal("text/html; charset=UTF-8"))
+		Expect(string(decodePart(part))).To(Equal(body))
+
+		for _, attachment := range attachments {
+			part, err := reader.NextPart()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(part.FileName()).To(Equal(attachment.Filename))
+			contentType, params, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params).To(HaveKeyWithValue("name", attachment.Filename))
+			if attachment.ContentType == "" {
+				Expect(contentType).To(Equal("application/octet-stream"))
+			} else {
+				Expect(contentType).To(Equal("text/csv"))
+				Expect(params).To(HaveKeyWithValue("charset", "utf-8"))
+			}
+			Expect(decodePart(part)).To(Equal(attachment.Data))
+		}
+
+		_, err = reader.NextPart()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("should return write errors", func() {
+		Expect(writeBase64(&failingWriter{remaining: 10}, bytes.Repeat([]byte("a"), 200))).To(MatchError("disk full"))
+		Expect(writeBase64(&failingWriter{remaining: 1000}, bytes.Repeat([]byte("a"), 200))).To(Succeed())
+	})
+})
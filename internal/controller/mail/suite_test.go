@@ -0,0 +1,14 @@
+package mail
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMail(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Mail Suite")
+}
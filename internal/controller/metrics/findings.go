@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/report"
+)
+
+// 巡检结果指标的标签
+var findingLabels = []string{"namespace", "name", "node", "metric"}
+
+var (
+	findingValueDesc = prometheus.NewDesc(namespace+"_finding_value",
+		"最近一次巡检的指标当前值：使用率为百分比，forecast为预计写满天数，reachability可达为1",
+		findingLabels, nil)
+	findingComparisonDesc = prometheus.NewDesc(namespace+"_finding_comparison",
+		"最近一次巡检中使用率在主对比偏移时的对比值（百分比）",
+		findingLabels, nil)
+	findingBaselineDesc = prometheus.NewDesc(namespace+"_finding_baseline",
+		"最近一次巡检中使用率动态基线的均值（百分比）",
+		findingLabels, nil)
+	findingDeltaDesc = prometheus.NewDesc(namespace+"_finding_delta",
+		"最近一次巡检中使用率与对比值之差（百分点）",
+		findingLabels, nil)
+	findingSeverityDesc = prometheus.NewDesc(namespace+"_finding_severity",
+		"最近一次巡检的严重程度：0正常，1已确认，2异常，3宕机或数据过期",
+		findingLabels, nil)
+	findingDroppedDesc = prometheus.NewDesc(namespace+"_finding_dropped_nodes",
+		"超过导出上限未导出巡检结果的主机数",
+		[]string{"namespace", "name"}, nil)
+)
+
+// inspectionFindings 一个AutoInspection最近一次巡检的结果
+type inspectionFindings struct {
+	namespace string
+	name      string
+	findings  []report.Finding
+	dropped   int
+}
+
+// findingsCollector 按AutoInspection保存最近一次巡检的结果，采集时生成指标。
+// 每次巡检整体替换结果，已消失的主机不会残留时间序列
+type findingsCollector struct {
+	mu          sync.RWMutex
+	inspections map[string]inspectionFindings
+}
+
+// Findings 巡检结果指标
+var Findings = &findingsCollector{inspections: map[string]inspectionFindings{}}
+
+// Describe 实现prometheus.Collector
+func (c *findingsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- findingValueDesc
+	ch <- findingComparisonDesc
+	ch <- findingDeltaDesc
+	ch <- findingBaselineDesc
+	ch <- findingSeverityDesc
+	ch <- findingDroppedDesc
+}
+
+// Collect 实现prometheus.Collector
+func (c *findingsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, inspection := range c.inspections {
+		for _, finding := range inspection.findings {
+			labels := []string{inspection.namespace, inspection.name, finding.Node, finding.Metric}
+			ch <- prometheus.MustNewConstMetric(findingValueDesc, prometheus.GaugeValue, finding.Value, labels...)
+			ch <- prometheus.MustNewConstMetric(findingSeverityDesc, prometheus.GaugeValue, float64(finding.Severity), labels...)
+			if finding.Compared {
+				ch <- prometheus.MustNewConstMetric(findingComparisonDesc, prometheus.GaugeValue, finding.Comparison, labels...)
+				ch <- prometheus.MustNewConstMetric(findingDeltaDesc, prometheus.GaugeValue, finding.Delta, labels...)
+			}
+			if finding.HasBaseline {
+				ch <- prometheus.MustNewConstMetric(findingBaselineDesc, prometheus.GaugeValue, finding.BaselineMean, labels...)
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(findingDroppedDesc, prometheus.GaugeValue, float64(inspection.dropped),
+			inspection.namespace, inspection.name)
+	}
+}
+
+// Set 替换AutoInspection最近一次巡检的结果，dropped为超过导出上限未导出的主机数
+func (c *findingsCollector) Set(namespace, name string, findings []report.Finding, dropped int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inspections[namespace+"/"+name] = inspectionFindings{
+		namespace: namespace,
+		name:      name,
+		findings:  uniqueFindings(findings),
+		dropped:   dropped,
+	}
+}
+
+// uniqueFindings 按主机和指标去重，保留严重程度最高的结果。
+// 不同target解析出相同主机名时标签完全相同，重复的时间序列会导致Gather失败
+func uniqueFindings(findings []report.Finding) []report.Finding {
+	index := make(map[[2]string]int, len(findings))
+	unique := make([]report.Finding, 0, len(findings))
+	for _, finding := range findings {
+		key := [2]string{finding.Node, finding.Metric}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(unique)
+			unique = append(unique, finding)
+			continue
+		}
+		if finding.Severity > unique[i].Severity {
+			unique[i] = finding
+		}
+	}
+	return unique
+}
+
+// Delete 删除AutoInspection的巡检结果
+func (c *findingsCollector) Delete(namespace, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inspections, namespace+"/"+name)
+}
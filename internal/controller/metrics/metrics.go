@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// 指标名称前缀
+const namespace = "auto_inspection"
+
+// 巡检结果标签的取值
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// 通知渠道标签的取值
+const (
+	ChannelMail    = "mail"
+	ChannelWebhook = "webhook"
+)
+
+// Prometheus查询类型标签的取值
+const (
+	QueryInstant = "query"
+	QueryRange   = "query_range"
+)
+
+var (
+	// RunDuration 每次巡检的耗时
+	RunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "run_duration_seconds",
+		Help:      "巡检任务的执行耗时（秒）",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	}, []string{"namespace", "name", "job"})
+
+	// RunsTotal 按结果统计的巡检次数
+	RunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "runs_total",
+		Help:      "巡检任务的执行次数，按结果区分",
+	}, []string{"namespace", "name", "job", "result"})
+
+	// LastSuccessTimestamp 最近一次巡检成功的时间
+	LastSuccessTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_success_timestamp_seconds",
+		Help:      "最近一次巡检成功的Unix时间戳",
+	}, []string{"namespace", "name", "job"})
+
+	// NextRunTimestamp 下一次计划执行的时间
+	NextRunTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "next_run_timestamp_seconds",
+		Help:      "巡检任务下一次计划执行的Unix时间戳",
+	}, []string{"namespace", "name", "job"})
+
+	// QueryDuration Prometheus查询的耗时
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "prometheus_query_duration_seconds",
+		Help:      "向Prometheus发起查询的耗时（秒）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type"})
+
+	// QueryErrorsTotal Prometheus查询失败的次数
+	QueryErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prometheus_query_errors_total",
+		Help:      "向Prometheus发起查询失败的次数",
+	}, []string{"type"})
+
+	// QueryCacheHitsTotal 命中查询缓存、没有请求Prometheus的查询次数
+	QueryCacheHitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prometheus_query_cache_hits_total",
+		Help:      "命中查询缓存、没有向Prometheus发起请求的查询次数",
+	}, []string{"type"})
+
+	// NotificationsTotal 按渠道和结果统计的通知发送次数
+	NotificationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "巡检报告通知的发送次数，按渠道和结果区分",
+	}, []string{"channel", "result"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		RunDuration,
+		RunsTotal,
+		LastSuccessTimestamp,
+		NextRunTimestamp,
+		QueryDuration,
+		QueryErrorsTotal,
+		QueryCacheHitsTotal,
+		NotificationsTotal,
+		Findings,
+	)
+}
+
+// result 按错误返回结果标签
+func result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+// ObserveRun 记录一次巡检的耗时和结果，成功时同时更新最近成功时间
+func ObserveRun(namespace, name, job string, start time.Time, err error) {
+	RunDuration.WithLabelValues(namespace, name, job).Observe(time.Since(start).Seconds())
+	RunsTotal.WithLabelValues(namespace, name, job, result(err)).Inc()
+	if err == nil {
+		LastSuccessTimestamp.WithLabelValues(namespace, name, job).SetToCurrentTime()
+	}
+}
+
+// SetNextRun 记录巡检任务下一次计划执行的时间
+func SetNextRun(namespace, name, job string, next time.Time) {
+	NextRunTimestamp.WithLabelValues(namespace, name, job).Set(float64(next.Unix()))
+}
+
+// ObserveQuery 记录一次Prometheus查询的耗时，失败时增加错误计数
+func ObserveQuery(queryType string, start time.Time, err error) {
+	QueryDuration.WithLabelValues(queryType).Observe(time.Since(start).Seconds())
+	if err != nil {
+		QueryErrorsTotal.WithLabelValues(queryType).Inc()
+	}
+}
+
+// ObserveQueryCacheHit 记录一次命中缓存的查询
+func ObserveQueryCacheHit(queryType string) {
+	QueryCacheHitsTotal.WithLabelValues(queryType).Inc()
+}
+
+// ObserveNotification 记录一次通知发送的结果
+func ObserveNotification(channel string, err error) {
+	NotificationsTotal.WithLabelValues(channel, result(err)).Inc()
+}
+
+// DeleteInspection 删除AutoInspection对应的全部时间序列，在资源被删除后调用
+func DeleteInspection(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	RunDuration.DeletePartialMatch(labels)
+	RunsTotal.DeletePartialMatch(labels)
+	LastSuccessTimestamp.DeletePartialMatch(labels)
+	NextRunTimestamp.DeletePartialMatch(labels)
+	Findings.Delete(namespace, name)
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/report"
+)
+
+var _ = Describe("Metrics", func() {
+	BeforeEach(func() {
+		RunDuration.Reset()
+		RunsTotal.Reset()
+		LastSuccessTimestamp.Reset()
+		NextRunTimestamp.Reset()
+		QueryDuration.Reset()
+		QueryErrorsTotal.Reset()
+		NotificationsTotal.Reset()
+	})
+
+	It("should be registered on the controller-runtime registry", func() {
+		ObserveRun("default", "prod", "daily", time.Now(), nil)
+		ObserveQuery(QueryInstant, time.Now(), nil)
+		ObserveNotification(ChannelMail, nil)
+
+		count, err := testutil.GatherAndCount(ctrlmetrics.Registry,
+			"auto_inspection_runs_total",
+			"auto_inspection_prometheus_query_duration_seconds",
+			"auto_inspection_notifications_total",
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(3))
+	})
+
+	It("should count run outcomes and record the last success", func() {
+		ObserveRun("default", "prod", "daily", time.Now(), nil)
+		ObserveRun("default", "prod", "daily", time.Now(), errors.New("timeout"))
+
+		Expect(testutil.ToFloat64(RunsTotal.WithLabelValues("default", "prod", "daily", ResultSuccess))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(RunsTotal.WithLabelValues("default", "prod", "daily", ResultFailure))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(LastSuccessTimestamp.WithLabelValues("default", "prod", "daily"))).
+			To(BeNumerically("~", float64(time.Now().Unix()), 5))
+		Expect(testutil.CollectAndCount(RunDuration)).To(Equal(1))
+	})
+
+	It("should count query errors by type", func() {
+		ObserveQuery(QueryRange, time.Now(), errors.New("unexpected status code: 503"))
+		ObserveQuery(QueryRange, time.Now(), nil)
+
+		Expect(testutil.ToFloat64(QueryErrorsTotal.WithLabelValues(QueryRange))).To(Equal(1.0))
+		Expect(testutil.CollectAndCount(QueryErrorsTotal)).To(Equal(1))
+	})
+
+	It("should record notification outcomes per channel", func() {
+		ObserveNotification(ChannelWebhook, errors.New("unexpected status code: 500"))
+		ObserveNotification(ChannelMail, nil)
+
+		expected := `
+# HELP auto_inspection_notifications_total 巡检报告通知的发送次数，按渠道和结果区分
+# TYPE auto_inspection_notifications_total counter
+auto_inspection_notifications_total{channel="mail",result="success"} 1
+auto_inspection_notifications_total{channel="webhook",result="failure"} 1
+`
+		Expect(testutil.CollectAndCompare(NotificationsTotal, strings.NewReader(expected))).To(Succeed())
+	})
+
+	It("should remove the series of a deleted AutoInspection", func() {
+		next := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+		SetNextRun("default", "prod", "daily", next)
+		SetNextRun("default", "test", "daily", next)
+		ObserveRun("default", "prod", "daily", time.Now(), nil)
+
+		Expect(testutil.ToFloat64(NextRunTimestamp.WithLabelValues("default", "prod", "daily"))).To(Equal(float64(next.Unix())))
+
+		DeleteInspection("default", "prod")
+		Expect(testutil.CollectAndCount(NextRunTimestamp)).To(Equal(1))
+		Expect(testutil.CollectAndCount(RunsTotal)).To(Equal(0))
+		Expect(testutil.CollectAndCount(LastSuccessTimestamp)).To(Equal(0))
+	})
+})
+
+var _ = Describe("Findings", func() {
+	AfterEach(func() {
+		Findings.Delete("default", "prod")
+		Findings.Delete("default", "test")
+	})
+
+	It("should export the latest findings and replace stale nodes", func() {
+		Findings.Set("default", "prod", []report.Finding{
+			{Node: "10.0.0.1:9100", Metric: report.MetricDisk, Value: 91.2, Comparison: 78.6, Delta: 12.6, Compared: true, Severity: report.SeverityWarning},
+			{Node: "10.0.0.1:9100", Metric: report.MetricCPU, Value: 72.8, Comparison: 40.3, Delta: 32.5, Compared: true, BaselineMean: 35, HasBaseline: true},
+			{Node: "10.0.0.2:9100", Metric: report.MetricReachability, Severity: report.SeverityCritical},
+		}, 0)
+
+		expected := `
+# HELP auto_inspection_finding_severity 最近一次巡检的严重程度：0正常，1已确认，2异常，3宕机或数据过期
+# TYPE auto_inspection_finding_severity gauge
+auto_inspection_finding_severity{metric="cpu",name="prod",namespace="default",node="10.0.0.1:9100"} 0
+auto_inspection_finding_severity{metric="disk",name="prod",namespace="default",node="10.0.0.1:9100"} 2
+auto_inspection_finding_severity{metric="reachability",name="prod",namespace="default",node="10.0.0.2:9100"} 3
+# HELP auto_inspection_finding_comparison 最近一次巡检中使用率在主对比偏移时的对比值（百分比）
+# TYPE auto_inspection_finding_comparison gauge
+auto_inspection_finding_comparison{metric="cpu",name="prod",namespace="default",node="10.0.0.1:9100"} 40.3
+auto_inspection_finding_comparison{metric="disk",name="prod",namespace="default",node="10.0.0.1:9100"} 78.6
+# HELP auto_inspection_finding_delta 最近一次巡检中使用率与对比值之差（百分点）
+# TYPE auto_inspection_finding_delta gauge
+auto_inspection_finding_delta{metric="cpu",name="prod",namespace="default",node="10.0.0.1:9100"} 32.5
+auto_inspection_finding_delta{metric="disk",name="prod",namespace="default",node="10.0.0.1:9100"} 12.6
+# HELP auto_inspection_finding_baseline 最近一次巡检中使用率动态基线的均值（百分比）
+# TYPE auto_inspection_finding_baseline gauge
+auto_inspection_finding_baseline{metric="cpu",name="prod",namespace="default",node="10.0.0.1:9100"} 35
+`
+		Expect(testutil.CollectAndCompare(Findings, strings.NewReader(expected),
+			"auto_inspection_finding_severity", "auto_inspection_finding_comparison",
+			"auto_inspection_finding_delta", "auto_inspection_finding_baseline")).To(Succeed())
+
+		Findings.Set("default", "prod", []report.Finding{
+			{Node: "10.0.0.1:9100", Metric: report.MetricDisk, Value: 50, Compared: true},
+		}, 3)
+		expected = `
+# HELP auto_inspection_finding_severity 最近一次巡检的严重程度：0正常，1已确认，2异常，3宕机或数据过期
+# TYPE auto_inspection_finding_severity gauge
+auto_inspection_finding_severity{metric="disk",name="prod",namespace="default",node="10.0.0.1:9100"} 0
+# HELP auto_inspection_finding_dropped_nodes 超过导出上限未导出巡检结果的主机数
+# TYPE auto_inspection_finding_dropped_nodes gauge
+auto_inspection_finding_dropped_nodes{name="prod",namespace="default"} 3
+`
+		Expect(testutil.CollectAndCompare(Findings, strings.NewReader(expected),
+			"auto_inspection_finding_severity", "auto_inspection_finding_dropped_nodes")).To(Succeed())
+	})
+
+	It("should export a single series when nodes share a name", func() {
+		Findings.Set("default", "prod", []report.Finding{
+			{Node: "node-1", Metric: report.MetricCPU, Value: 40},
+			{Node: "node-1", Metric: report.MetricCPU, Value: 95, Severity: report.SeverityWarning},
+			{Node: "node-1", Metric: report.MetricMemory, Value: 30},
+		}, 0)
+
+		expected := `
+# HELP auto_inspection_finding_value 最近一次巡检的指标当前值：使用率为百分比，forecast为预计写满天数，reachability可达为1
+# TYPE auto_inspection_finding_value gauge
+auto_inspection_finding_value{metric="cpu",name="prod",namespace="default",node="node-1"} 95
+auto_inspection_finding_value{metric="memory",name="prod",namespace="default",node="node-1"} 30
+`
+		Expect(testutil.CollectAndCompare(Findings, strings.NewReader(expected),
+			"auto_inspection_finding_value")).To(Succeed())
+	})
+
+	It("should remove the findings of a deleted AutoInspection", func() {
+		Findings.Set("default", "prod", []report.Finding{{Node: "10.0.0.1:9100", Metric: report.MetricCPU}}, 0)
+		Findings.Set("default", "test", []report.Finding{{Node: "10.0.0.2:9100", Metric: report.MetricCPU}}, 0)
+
+		DeleteInspection("default", "prod")
+		count, err := testutil.GatherAndCount(ctrlmetrics.Registry, "auto_inspection_finding_value")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(1))
+	})
+})
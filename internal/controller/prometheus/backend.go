@@ -0,0 +1,93 @@
+package prometheus
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Backend 兼容Prometheus HTTP API的存储后端类型
+type Backend string
+
+const (
+	BackendPrometheus      Backend = "Prometheus"
+	BackendVictoriaMetrics Backend = "VictoriaMetrics"
+	BackendThanos          Backend = "Thanos"
+	BackendMimir           Backend = "Mimir"
+)
+
+// 多租户后端的请求头
+const (
+	headerMimirTenant  = "X-Scope-OrgID"
+	headerThanosTenant = "THANOS-TENANT"
+)
+
+// BackendOptions 后端相关的选项，决定请求的路径、参数和请求头
+type BackendOptions struct {
+	// 后端类型，为空时按Prometheus处理
+	Type Backend
+	// API路径前缀，如VictoriaMetrics集群版的 /select/0/prometheus，为空时使用后端的默认前缀
+	PathPrefix string
+	// 租户，Mimir和Thanos通过请求头传递，VictoriaMetrics集群版在未配置PathPrefix时放在路径中
+	Tenant string
+	// Thanos是否对高可用副本的数据去重，为空时去重
+	Dedup *bool
+	// Store或存储节点部分不可用时是否返回部分结果，为空时使用后端的默认行为。
+	// Thanos对应partial_response参数，VictoriaMetrics对应deny_partial_response参数
+	PartialResponse *bool
+}
+
+// pathPrefix 返回API路径前缀，不以/结尾
+func (o BackendOptions) pathPrefix() string {
+	if o.PathPrefix != "" {
+		return "/" + strings.Trim(o.PathPrefix, "/")
+	}
+	switch o.Type {
+	case BackendMimir:
+		return "/prometheus"
+	case BackendVictoriaMetrics:
+		// 集群版vmselect按租户区分路径，单机版没有租户
+		if o.Tenant != "" {
+			return "/select/" + url.PathEscape(o.Tenant) + "/prometheus"
+		}
+	}
+	return ""
+}
+
+// endpoint 返回API的完整地址，如 http://thanos-query:9090/api/v1/query
+func (c *Client) endpoint(api string) string {
+	return strings.TrimRight(c.URL, "/") + c.Backend.pathPrefix() + api
+}
+
+// setParams 添加后端相关的查询参数
+func (c *Client) setParams(q url.Values) {
+	switch c.Backend.Type {
+	case BackendThanos:
+		dedup := true
+		if c.Backend.Dedup != nil {
+			dedup = *c.Backend.Dedup
+		}
+		q.Set("dedup", strconv.FormatBool(dedup))
+		if c.Backend.PartialResponse != nil {
+			q.Set("partial_response", strconv.FormatBool(*c.Backend.PartialResponse))
+		}
+	case BackendVictoriaMetrics:
+		if c.Backend.PartialResponse != nil && !*c.Backend.PartialResponse {
+			q.Set("deny_partial_response", "1")
+		}
+	}
+}
+
+// setHeaders 添加后端相关的请求头
+func (c *Client) setHeaders(header http.Header) {
+	if c.Backend.Tenant == "" {
+		return
+	}
+	switch c.Backend.Type {
+	case BackendMimir:
+		header.Set(headerMimirTenant, c.Backend.Tenant)
+	case BackendThanos:
+		header.Set(headerThanosTenant, c.Backend.Tenant)
+	}
+}
@@ -0,0 +1,93 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backends", func() {
+	// request 记录假服务端收到的请求
+	type request struct {
+		path   string
+		params url.Values
+		header http.Header
+	}
+
+	var (
+		received []request
+		server   *httptest.Server
+		disabled = false
+	)
+
+	BeforeEach(func() {
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = r.ParseForm()
+			received = append(received, request{path: r.URL.Path, params: r.Form, header: r.Header.Clone()})
+			_, _ = w.Write([]byte(vectorResponse))
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	// query 使用指定的后端选项执行一次即时查询和一次范围查询
+	query := func(options BackendOptions) {
+		client := NewClient(server.URL + "/")
+		client.Backend = options
+		_, err := client.Query(context.Background(), "up", time.Time{})
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		_, err = client.QueryRange(context.Background(), "up", time.Now().Add(-time.Hour), time.Now(), time.Minute)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, received).To(HaveLen(2))
+	}
+
+	It("should query Prometheus without extra parameters", func() {
+		query(BackendOptions{})
+		Expect(received[0].path).To(Equal("/api/v1/query"))
+		Expect(received[1].path).To(Equal("/api/v1/query_range"))
+		Expect(received[0].params).NotTo(HaveKey("dedup"))
+		Expect(received[0].header.Get(headerMimirTenant)).To(BeEmpty())
+	})
+
+	It("should put the VictoriaMetrics tenant in the path", func() {
+		query(BackendOptions{Type: BackendVictoriaMetrics, Tenant: "0:1", PartialResponse: &disabled})
+		Expect(received[0].path).To(Equal("/select/0:1/prometheus/api/v1/query"))
+		Expect(received[1].path).To(Equal("/select/0:1/prometheus/api/v1/query_range"))
+		Expect(received[1].params.Get("deny_partial_response")).To(Equal("1"))
+	})
+
+	It("should prefer the configured path prefix", func() {
+		query(BackendOptions{Type: BackendVictoriaMetrics, Tenant: "0", PathPrefix: "vmselect/select/0/prometheus/"})
+		Expect(received[0].path).To(Equal("/vmselect/select/0/prometheus/api/v1/query"))
+		Expect(received[0].params).NotTo(HaveKey("deny_partial_response"))
+	})
+
+	It("should deduplicate Thanos replicas by default", func() {
+		query(BackendOptions{Type: BackendThanos, Tenant: "team-a", PartialResponse: &disabled})
+		for _, r := range received {
+			Expect(r.path).To(HavePrefix("/api/v1/"))
+			Expect(r.params.Get("dedup")).To(Equal("true"))
+			Expect(r.params.Get("partial_response")).To(Equal("false"))
+			Expect(r.header.Get(headerThanosTenant)).To(Equal("team-a"))
+		}
+	})
+
+	It("should allow disabling Thanos deduplication", func() {
+		query(BackendOptions{Type: BackendThanos, Dedup: &disabled})
+		Expect(received[0].params.Get("dedup")).To(Equal("false"))
+		Expect(received[0].params).NotTo(HaveKey("partial_response"))
+	})
+
+	It("should send the Mimir tenant header", func() {
+		query(BackendOptions{Type: BackendMimir, Tenant: "ops"})
+		for _, r := range received {
+			Expect(r.path).To(HavePrefix("/prometheus/api/v1/"))
+			Expect(r.header.Get(headerMimirTenant)).To(Equal("ops"))
+		}
+	})
+})
@@ -0,0 +1,125 @@
+package prometheus
+
+import (
+	"sync"
+	"time"
+)
+
+// 熔断器的默认配置
+const (
+	// DefaultBreakerThreshold 连续失败该次数后打开熔断器
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerCooldown 熔断器打开后经过该时长允许一次试探请求
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// 熔断器状态
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker 按Prometheus地址统计连续失败次数的熔断器。
+// 连续失败达到阈值后打开，冷却期内的请求直接返回ErrCircuitOpen；
+// 冷却期过后只放行一次试探请求，成功后关闭，失败后重新打开
+type CircuitBreaker struct {
+	// 连续失败该次数后打开
+	Threshold int
+	// 打开后等待该时长再试探
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown, now: time.Now}
+}
+
+// Allow 判断是否允许发起请求，熔断器打开时返回ErrCircuitOpen
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// 试探请求返回前不放行其他请求
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// Record 记录请求结果
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.Threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Release 放弃本次请求的结果，不计入成功或失败，用于请求被取消的情况。
+// 半开状态下退回打开状态并保留原打开时间，下一个请求可以立即重新试探
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+// Open 熔断器是否处于打开状态
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state != breakerClosed
+}
+
+// breakers 按后端地址和租户共享的熔断器，同一后端的多个巡检共用熔断状态
+var breakers = struct {
+	sync.Mutex
+	byKey map[string]*CircuitBreaker
+}{byKey: map[string]*CircuitBreaker{}}
+
+// breakerFor 返回后端地址和租户对应的熔断器
+func breakerFor(key string) *CircuitBreaker {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	breaker, ok := breakers.byKey[key]
+	if !ok {
+		breaker = NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown)
+		breakers.byKey[key] = breaker
+	}
+	return breaker
+}
+
+// breaker 返回请求使用的熔断器，没有熔断器时返回nil。
+// 与查询缓存一样按路径前缀解析后的地址和租户区分，同一地址上的不同租户或VictoriaMetrics集群租户分别熔断
+func (c *Client) breaker() *CircuitBreaker {
+	if c.Breaker != nil || !c.sharedBreaker {
+		return c.Breaker
+	}
+	return breakerFor(c.Backend.Tenant + " " + c.endpoint(""))
+}
@@ -0,0 +1,72 @@
+package prometheus
+
+import (
+	"sync"
+	"time"
+)
+
+// QueryCache 短时间内缓存成功的查询结果，同一次巡检中相同的查询只请求一次。
+// 为nil时不缓存，可以在多个客户端之间共享
+type QueryCache struct {
+	ttl time.Duration
+	// 当前时间，测试时可以替换
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry 缓存的查询结果
+type cacheEntry struct {
+	result  *QueryResult
+	expires time.Time
+}
+
+// NewQueryCache 创建查询缓存，结果在ttl后过期
+func NewQueryCache(ttl time.Duration) *QueryCache {
+	return &QueryCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Get 返回未过期的缓存结果的副本
+func (c *QueryCache) Get(key string) (*QueryResult, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	result := *entry.result
+	return &result, true
+}
+
+// Put 缓存查询结果
+func (c *QueryCache) Put(key string, result *QueryResult) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{result: result, expires: c.now().Add(c.ttl)}
+}
+
+// Len 返回缓存的结果数，包括已过期但尚未清理的结果
+func (c *QueryCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
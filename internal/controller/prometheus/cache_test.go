@@ -0,0 +1,114 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/metrics"
+)
+
+var _ = Describe("Request method", func() {
+	var (
+		methods   []string
+		allowPost bool
+		client    *Client
+	)
+
+	BeforeEach(func() {
+		methods = nil
+		allowPost = true
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+			if r.Method == http.MethodPost && !allowPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.FormValue("query") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(vectorResponse))
+		}))
+		DeferCleanup(server.Close)
+		client = NewClient(server.URL)
+		client.Breaker = NewCircuitBreaker(DefaultBreakerThreshold, time.Minute)
+	})
+
+	It("should send queries as form-encoded POST requests", func() {
+		_, err := client.Query(context.Background(), "up", time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(methods).To(Equal([]string{http.MethodPost}))
+	})
+
+	It("should fall back to GET when POST is not allowed", func() {
+		allowPost = false
+		for range 2 {
+			_, err := client.Query(context.Background(), "up", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(methods).To(Equal([]string{http.MethodPost, http.MethodGet, http.MethodGet}))
+	})
+})
+
+var _ = Describe("QueryCache", func() {
+	var (
+		requests int
+		client   *Client
+		cache    *QueryCache
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			_, _ = w.Write([]byte(vectorResponse))
+		}))
+		DeferCleanup(server.Close)
+		cache = NewQueryCache(time.Minute)
+		client = NewClient(server.URL)
+		client.Breaker = NewCircuitBreaker(DefaultBreakerThreshold, time.Minute)
+		client.Cache = cache
+	})
+
+	It("should evaluate identical queries once", func() {
+		hits := testutil.ToFloat64(metrics.QueryCacheHitsTotal.WithLabelValues(metrics.QueryInstant))
+		now := time.Now()
+		for range 3 {
+			result, err := client.Query(context.Background(), "up", now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ParseValue(result)).To(Equal(1.0))
+		}
+		Expect(requests).To(Equal(1))
+		Expect(testutil.ToFloat64(metrics.QueryCacheHitsTotal.WithLabelValues(metrics.QueryInstant))).To(Equal(hits + 2))
+
+		_, err := client.Query(context.Background(), "up", now.Add(-24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(Equal(2))
+	})
+
+	It("should expire cached results", func() {
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+		_, err := client.Query(context.Background(), "up", now)
+		Expect(err).NotTo(HaveOccurred())
+
+		cache.now = func() time.Time { return now.Add(time.Minute) }
+		_, err = client.Query(context.Background(), "up", now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(Equal(2))
+	})
+
+	It("should not cache failed queries", func() {
+		client.URL = "http://127.0.0.1:0"
+		client.Retry = RetryPolicy{MaxAttempts: 1}
+		_, err := client.Query(context.Background(), "up", time.Now())
+		Expect(err).To(HaveOccurred())
+		Expect(cache.Len()).To(BeZero())
+	})
+})
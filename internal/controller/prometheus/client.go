@@ -3,229 +3,298 @@ package prometheus
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/metrics"
+	"github.com/rxg456/auto-inspection-operator/internal/controller/tracing"
 )
 
 // Client 是Prometheus API客户端
 type Client struct {
 	URL    string
 	Client *http.Client
+	// 查询失败时的重试策略
+	Retry RetryPolicy
+	// 熔断器，为空时NewClient创建的客户端使用同一后端地址和租户共享的熔断器，否则不熔断
+	Breaker *CircuitBreaker
+	// 存储后端相关的选项，零值按Prometheus处理
+	Backend BackendOptions
+	// 查询结果缓存，为空时不缓存
+	Cache *QueryCache
+
+	// 未指定Breaker时是否使用共享的熔断器
+	sharedBreaker bool
+	// 服务端不支持POST查询时改用GET
+	getOnly atomic.Bool
+
+	mu sync.Mutex
+	// 查询响应中的警告
+	warnings []string
 }
 
-// NewClient 创建一个新的Prometheus客户端
+// NewClient 创建一个新的Prometheus客户端，使用默认的重试策略和共享的熔断器。
+// 熔断器在请求时按后端地址和租户查找，创建后设置Backend也能使用正确的熔断器
 func NewClient(prometheusURL string) *Client {
 	return &Client{
 		URL: prometheusURL,
 		Client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		Retry:         DefaultRetryPolicy(),
+		sharedBreaker: true,
 	}
 }
 
-// QueryResult 表示Prometheus查询结果
-type QueryResult struct {
-	Status string `json:"status"`
-	Data   struct {
-		ResultType string `json:"resultType"`
-		Result     []struct {
-			Metric map[string]string `json:"metric"`
-			Value  []interface{}     `json:"value"`
-		} `json:"result"`
-	} `json:"data"`
+// Query 执行Prometheus即时查询，并记录查询耗时和失败次数
+func (c *Client) Query(ctx context.Context, query string, timestamp time.Time) (*QueryResult, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	if !timestamp.IsZero() {
+		params.Set("time", timestamp.Format(time.RFC3339))
+	}
+	return c.execute(ctx, "Prometheus.Query", metrics.QueryInstant, "/api/v1/query", params)
 }
 
-// QueryRange 表示Prometheus范围查询结果
-type QueryRangeResult struct {
-	Status string `json:"status"`
-	Data   struct {
-		ResultType string `json:"resultType"`
-		Result     []struct {
-			Metric map[string]string `json:"metric"`
-			Values [][]interface{}   `json:"values"`
-		} `json:"result"`
-	} `json:"data"`
+// QueryRange 执行Prometheus范围查询，并记录查询耗时和失败次数
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*QueryRangeResult, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", start.Format(time.RFC3339))
+	params.Set("end", end.Format(time.RFC3339))
+	params.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+	return c.execute(ctx, "Prometheus.QueryRange", metrics.QueryRange, "/api/v1/query_range", params)
 }
 
-// Query 执行Prometheus即时查询
-func (c *Client) Query(ctx context.Context, query string, timestamp time.Time) (*QueryResult, error) {
-	u, err := url.Parse(fmt.Sprintf("%s/api/v1/query", c.URL))
+// execute 执行查询，命中缓存时直接返回缓存的结果，否则发送请求并记录查询耗时、失败次数和追踪
+func (c *Client) execute(ctx context.Context, spanName, queryType, api string, params url.Values) (*QueryResult, error) {
+	c.setParams(params)
+	endpoint := c.endpoint(api)
+	key := c.cacheKey(endpoint, params)
+	if result, ok := c.Cache.Get(key); ok {
+		metrics.ObserveQueryCacheHit(queryType)
+		return result, nil
+	}
+
+	ctx, span := tracing.Start(ctx, spanName,
+		tracing.AttrQueryType.String(queryType), tracing.AttrQuery.String(params.Get("query")))
+	start := time.Now()
+	var result QueryResult
+	err := c.request(ctx, endpoint, params, &result)
+	metrics.ObserveQuery(queryType, start, err)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, err
 	}
 
-	q := u.Query()
-	q.Set("query", query)
-	if !timestamp.IsZero() {
-		q.Set("time", timestamp.Format(time.RFC3339))
-	}
-	u.RawQuery = q.Encode()
+	c.Cache.Put(key, &result)
+	return &result, nil
+}
+
+// cacheKey 返回查询的缓存键，不同租户的相同查询结果不同
+func (c *Client) cacheKey(endpoint string, params url.Values) string {
+	return c.Backend.Tenant + " " + endpoint + "?" + params.Encode()
+}
+
+// request 发送查询请求并解析响应，临时错误按重试策略重试。
+// 优先使用表单编码的POST请求，避免较长的标签选择器超出URL长度限制；
+// 服务端或代理不支持POST时改用GET，之后的请求直接使用GET。查询只读取数据，重复请求不会产生副作用
+func (c *Client) request(ctx context.Context, endpoint string, params url.Values, out *QueryResult) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		if !c.getOnly.Load() {
+			err := c.send(ctx, http.MethodPost, endpoint, params, out)
+			if !postUnsupported(err) {
+				return err
+			}
+			trace.SpanFromContext(ctx).AddEvent("fallback_to_get", trace.WithAttributes(
+				attribute.String("error", err.Error()),
+			))
+			c.getOnly.Store(true)
+		}
+		return c.send(ctx, http.MethodGet, endpoint, params, out)
+	})
+}
+
+// postUnsupported 判断请求失败是否因为服务端不支持POST
+func postUnsupported(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusMethodNotAllowed || statusErr.StatusCode == http.StatusNotImplemented)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+// send 按指定方法发送一次请求并解析响应
+func (c *Client) send(ctx context.Context, method, endpoint string, params url.Values, out *QueryResult) error {
+	*out = QueryResult{}
+	var body io.Reader
+	if method == http.MethodPost {
+		body = strings.NewReader(params.Encode())
+	} else {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	c.setHeaders(req.Header)
 
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+		return newStatusError(resp, time.Now())
 	}
 
-	var result QueryResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return err
 	}
-
-	return &result, nil
+	if out.Status == "error" {
+		return &StatusError{StatusCode: resp.StatusCode, ErrorType: out.ErrorType, Message: out.Error}
+	}
+	c.addWarnings(out.Warnings)
+	return nil
 }
 
-// 获取CPU使用率查询
-func CPUUsageQuery(instance string, labels map[string]string) string {
-	var conditions []string
-
-	// 添加instance条件（如果提供）
-	if instance != "" {
-		conditions = append(conditions, fmt.Sprintf(`instance="%s"`, instance))
+// addWarnings 记录查询响应中的警告，相同的警告只保留一条
+func (c *Client) addWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
 	}
-
-	// 添加mode=idle条件（必需的）
-	conditions = append(conditions, `mode="idle"`)
-
-	// 添加labels条件（如果提供）
-	if labels != nil {
-		for k, v := range labels {
-			conditions = append(conditions, fmt.Sprintf(`%s="%s"`, k, v))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, warning := range warnings {
+		if !slices.Contains(c.warnings, warning) {
+			c.warnings = append(c.warnings, warning)
 		}
 	}
+}
 
-	// 用逗号连接所有条件
-	selectorStr := strings.Join(conditions, ",")
+// Warnings 返回客户端创建以来Prometheus返回的所有警告，按首次出现的顺序排列
+func (c *Client) Warnings() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.warnings)
+}
 
+// 获取CPU使用率查询
+func CPUUsageQuery(instance string, matchers []Matcher) string {
+	selectorStr := nodeSelector(instance, matchers, Matcher{Name: "mode", Type: MatchEqual, Value: "idle"}).String()
 	return fmt.Sprintf(`(1 - avg(irate(node_cpu_seconds_total{%s}[60m])) by (instance))*100`, selectorStr)
 }
 
 // 获取内存使用率查询
-func MemoryUsageQuery(instance string, labels map[string]string) string {
-	var conditions []string
-
-	// 添加instance条件（如果提供）
-	if instance != "" {
-		conditions = append(conditions, fmt.Sprintf(`instance="%s"`, instance))
-	}
+func MemoryUsageQuery(instance string, matchers []Matcher) string {
+	selectorStr := nodeSelector(instance, matchers).String()
+	return fmt.Sprintf(`(1 - (node_memory_MemAvailable_bytes{%s} / node_memory_MemTotal_bytes{%s}))*100`, selectorStr, selectorStr)
+}
 
-	// 添加labels条件（如果提供）
-	if labels != nil {
-		for k, v := range labels {
-			conditions = append(conditions, fmt.Sprintf(`%s="%s"`, k, v))
-		}
-	}
+// 获取节点抓取状态查询，1表示最近一次抓取成功，0表示抓取失败
+func NodeUpQuery(instance string, matchers []Matcher) string {
+	return fmt.Sprintf(`up{%s}`, nodeSelector(instance, matchers))
+}
 
-	// 用逗号连接所有条件
-	selectorStr := strings.Join(conditions, ",")
+// 获取节点最近一次抓取距今秒数的查询
+func ScrapeAgeQuery(instance string, matchers []Matcher) string {
+	return fmt.Sprintf(`time() - timestamp(up{%s})`, nodeSelector(instance, matchers))
+}
 
-	return fmt.Sprintf(`(1 - (node_memory_MemAvailable_bytes{%s} / node_memory_MemTotal_bytes{%s}))*100`, selectorStr, selectorStr)
+// FilesystemFilter 文件系统过滤条件，各字段为正则表达式列表，匹配任意一个即可
+type FilesystemFilter struct {
+	IncludeFSTypes     []string
+	ExcludeFSTypes     []string
+	IncludeMountpoints []string
+	ExcludeMountpoints []string
 }
 
-// 获取硬盘使用率查询
-func DiskUsageQuery(instance string, labels map[string]string) string {
-	var conditions []string
+// DefaultExcludeFSTypes 未配置排除规则时默认排除的内存文件系统
+var DefaultExcludeFSTypes = []string{"tmpfs", "rootfs"}
 
-	// 添加instance条件（如果提供）
-	if instance != "" {
-		conditions = append(conditions, fmt.Sprintf(`instance="%s"`, instance))
-	}
+// matchers 返回过滤条件对应的标签匹配器
+func (f FilesystemFilter) matchers() []Matcher {
+	var matchers []Matcher
 
-	// 添加labels条件（如果提供）
-	if labels != nil {
-		for k, v := range labels {
-			conditions = append(conditions, fmt.Sprintf(`%s="%s"`, k, v))
+	matcher := func(label string, matchType MatchType, patterns []string) {
+		if len(patterns) > 0 {
+			matchers = append(matchers, Matcher{Name: label, Type: matchType, Value: strings.Join(patterns, "|")})
 		}
 	}
 
-	// 用逗号连接所有条件
-	selectorStr := strings.Join(conditions, ",")
-
-	// 如果条件为空，添加一个fstype!=条件以排除特殊文件系统
-	if len(conditions) == 0 {
-		selectorStr = `fstype!="tmpfs",fstype!="rootfs"`
+	excludeFSTypes := f.ExcludeFSTypes
+	if excludeFSTypes == nil {
+		excludeFSTypes = DefaultExcludeFSTypes
 	}
 
-	return fmt.Sprintf(`max(100 - ((node_filesystem_avail_bytes{%s} / node_filesystem_size_bytes{%s}) * 100))`, selectorStr, selectorStr)
+	matcher("fstype", MatchRegex, f.IncludeFSTypes)
+	matcher("fstype", MatchNotRegex, excludeFSTypes)
+	matcher("mountpoint", MatchRegex, f.IncludeMountpoints)
+	matcher("mountpoint", MatchNotRegex, f.ExcludeMountpoints)
+	return matchers
 }
 
-// 获取inode使用率查询
-func InodeUsageQuery(instance string, labels map[string]string) string {
-	var conditions []string
-
-	// 添加instance条件（如果提供）
-	if instance != "" {
-		conditions = append(conditions, fmt.Sprintf(`instance="%s"`, instance))
-	}
-
-	// 添加labels条件（如果提供）
-	if labels != nil {
-		for k, v := range labels {
-			conditions = append(conditions, fmt.Sprintf(`%s="%s"`, k, v))
-		}
-	}
-
-	// 用逗号连接所有条件
-	selectorStr := strings.Join(conditions, ",")
+// filesystemSelector 构建文件系统指标的标签选择器，过滤条件始终生效
+func filesystemSelector(instance string, matchers []Matcher, filter FilesystemFilter) string {
+	return nodeSelector(instance, matchers, filter.matchers()...).String()
+}
 
-	// 如果条件为空，添加一个fstype!=条件以排除特殊文件系统
-	if len(conditions) == 0 {
-		selectorStr = `fstype!="tmpfs",fstype!="rootfs"`
-	}
+// 获取各分区硬盘使用率查询，结果保留device、mountpoint、fstype标签
+func FilesystemUsageQuery(instance string, matchers []Matcher, filter FilesystemFilter) string {
+	selectorStr := filesystemSelector(instance, matchers, filter)
+	return fmt.Sprintf(`100 - ((node_filesystem_avail_bytes{%s} / node_filesystem_size_bytes{%s}) * 100)`, selectorStr, selectorStr)
+}
 
-	return fmt.Sprintf(`max(100 - ((node_filesystem_files_free{%s} / node_filesystem_files{%s})*100))`, selectorStr, selectorStr)
+// 获取各分区inode使用率查询，结果保留device、mountpoint、fstype标签
+func FilesystemInodeQuery(instance string, matchers []Matcher, filter FilesystemFilter) string {
+	selectorStr := filesystemSelector(instance, matchers, filter)
+	return fmt.Sprintf(`100 - ((node_filesystem_files_free{%s} / node_filesystem_files{%s})*100)`, selectorStr, selectorStr)
 }
 
-// ParseValue 从查询结果中解析浮点值
-func ParseValue(result *QueryResult) (float64, error) {
-	if result == nil || len(result.Data.Result) == 0 {
-		return 0, fmt.Errorf("no results returned")
-	}
+// 获取硬盘使用率查询（取使用率最大的分区）
+func DiskUsageQuery(instance string, matchers []Matcher, filter FilesystemFilter) string {
+	return fmt.Sprintf(`max(%s)`, FilesystemUsageQuery(instance, matchers, filter))
+}
 
-	// 获取值
-	value := result.Data.Result[0].Value[1]
-	if strValue, ok := value.(string); ok {
-		var floatValue float64
-		_, err := fmt.Sscanf(strValue, "%f", &floatValue)
-		if err != nil {
-			return 0, err
-		}
-		return floatValue, nil
-	}
+// 获取inode使用率查询（取使用率最大的分区）
+func InodeUsageQuery(instance string, matchers []Matcher, filter FilesystemFilter) string {
+	return fmt.Sprintf(`max(%s)`, FilesystemInodeQuery(instance, matchers, filter))
+}
 
-	return 0, fmt.Errorf("failed to parse value: %v", value)
+// 获取磁盘写满预测查询，按window内可用空间的线性回归推算各分区写满所需的天数，
+// 只返回可用空间在减少的分区
+func DiskFullForecastQuery(instance string, matchers []Matcher, filter FilesystemFilter, window string) string {
+	selectorStr := filesystemSelector(instance, matchers, filter)
+	return fmt.Sprintf(`node_filesystem_avail_bytes{%s} / (-deriv(node_filesystem_avail_bytes{%s}[%s]) > 0) / 86400`,
+		selectorStr, selectorStr, window)
 }
 
 // GetNodesByLabels 根据标签查询所有符合条件的节点
-func (c *Client) GetNodesByLabels(ctx context.Context, labels map[string]string) ([]string, error) {
-	if labels == nil || len(labels) == 0 {
+func (c *Client) GetNodesByLabels(ctx context.Context, matchers []Matcher) ([]string, error) {
+	if len(matchers) == 0 {
 		return nil, fmt.Errorf("labels不能为空")
 	}
-
-	// 构建标签条件
-	var conditions []string
-	for k, v := range labels {
-		conditions = append(conditions, fmt.Sprintf(`%s="%s"`, k, v))
+	if !Selector(matchers).Selective() {
+		return nil, fmt.Errorf("labels至少需要一个不匹配空值的条件，只有否定条件时会选中所有抓取目标")
 	}
-	labelSelector := strings.Join(conditions, ",")
 
 	// 使用up指标查询符合标签条件的所有节点
 	// up指标通常存在于所有节点，用于表示节点是否在线
-	query := fmt.Sprintf(`up{%s}`, labelSelector)
+	query := fmt.Sprintf(`up{%s}`, Selector(matchers))
 
 	result, err := c.Query(ctx, query, time.Time{})
 	if err != nil {
@@ -249,6 +318,7 @@ func (c *Client) GetNodesByLabels(ctx context.Context, labels map[string]string)
 	for node := range nodeMap {
 		nodes = append(nodes, node)
 	}
+	slices.Sort(nodes)
 
 	return nodes, nil
 }
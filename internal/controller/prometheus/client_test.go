@@ -0,0 +1,92 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/tracing"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server   *httptest.Server
+		client   *Client
+		exporter *tracetest.InMemoryExporter
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.FormValue("query") {
+			case "bad":
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error: unexpected end of input"}`))
+				return
+			case "partial":
+				_, _ = w.Write([]byte(`{"status":"success","warnings":["store 10.0.0.9:10901 unavailable"],` +
+					`"data":{"resultType":"vector","result":[]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{"instance":"10.0.0.1:9100"},"value":[1700000000,"42.5"]}]}}`))
+		}))
+		DeferCleanup(server.Close)
+		client = NewClient(server.URL)
+
+		exporter = tracetest.NewInMemoryExporter()
+		provider := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), tracing.Config{SampleRatio: 1})
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(provider)
+		DeferCleanup(func() { otel.SetTracerProvider(previous) })
+	})
+
+	It("should trace queries with the PromQL expression", func() {
+		result, err := client.Query(context.Background(), `up{job="node"}`, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		value, err := ParseValue(result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(42.5))
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name).To(Equal("Prometheus.Query"))
+		Expect(spans[0].Attributes).To(ContainElement(tracing.AttrQuery.String(`up{job="node"}`)))
+	})
+
+	It("should mark failed queries as errors", func() {
+		_, err := client.Query(context.Background(), "bad", time.Time{})
+		Expect(err).To(HaveOccurred())
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Status.Code).To(Equal(codes.Error))
+	})
+
+	It("should report the error type and message of failed queries", func() {
+		_, err := client.Query(context.Background(), "bad", time.Time{})
+		var statusErr *StatusError
+		Expect(errors.As(err, &statusErr)).To(BeTrue())
+		Expect(statusErr.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(statusErr.ErrorType).To(Equal("bad_data"))
+		Expect(statusErr.Message).To(Equal("parse error: unexpected end of input"))
+	})
+
+	It("should collect warnings once", func() {
+		for range 2 {
+			result, err := client.Query(context.Background(), "partial", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Warnings).To(ConsistOf("store 10.0.0.9:10901 unavailable"))
+		}
+		Expect(client.Warnings()).To(Equal([]string{"store 10.0.0.9:10901 unavailable"}))
+	})
+})
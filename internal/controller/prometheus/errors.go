@@ -0,0 +1,104 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrCircuitOpen Prometheus连续失败后熔断器打开，冷却期内的查询直接失败
+var ErrCircuitOpen = errors.New("prometheus circuit breaker is open")
+
+// Prometheus API的错误类型中可以重试的类型
+const (
+	ErrorTypeTimeout     = "timeout"
+	ErrorTypeUnavailable = "unavailable"
+)
+
+// StatusError Prometheus返回了非200的响应，或响应的status为error
+type StatusError struct {
+	StatusCode int
+	Body       string
+	// 响应中的errorType和error，响应不是Prometheus API格式时为空
+	ErrorType string
+	Message   string
+	// 响应中Retry-After头指定的等待时间，未指定时为0
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.ErrorType != "" {
+		return fmt.Sprintf("prometheus %s error (status code %d): %s", e.ErrorType, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("unexpected status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// maxErrorBody 读取错误响应的最大字节数，只在错误信息中保留前1024字节
+const maxErrorBody = 64 * 1024
+
+// newStatusError 根据响应构造StatusError，响应为Prometheus API格式时解析错误类型
+func newStatusError(resp *http.Response, now time.Time) *StatusError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	err := &StatusError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body[:min(len(body), 1024)]),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), now),
+	}
+
+	var envelope QueryResult
+	if json.Unmarshal(body, &envelope) == nil && envelope.Status == "error" {
+		err.ErrorType = envelope.ErrorType
+		err.Message = envelope.Error
+	}
+	return err
+}
+
+// parseRetryAfter 解析Retry-After头，支持秒数和HTTP日期两种格式，无法解析时返回0
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// IsRetryable 判断查询错误是否为可重试的临时错误：
+// 网络错误、单次请求超时、429和5xx响应、timeout和unavailable类型的错误可以重试；
+// 其余4xx响应、响应解析失败、外部取消和熔断器打开为永久错误，重试不会成功
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.ErrorType == ErrorTypeTimeout || statusErr.ErrorType == ErrorTypeUnavailable {
+			return true
+		}
+		return statusErr.StatusCode == http.StatusTooManyRequests ||
+			(statusErr.StatusCode >= 500 && statusErr.StatusCode != http.StatusNotImplemented)
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,114 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Source 数据源
+type Source struct {
+	Name string
+	// 备用数据源，只在主数据源不可用时查询
+	Fallback bool
+	Client   *Client
+}
+
+// IsUnavailable 判断查询错误是否意味着数据源不可用，可以切换到其他数据源：
+// 重试后仍失败的临时错误和熔断器打开
+func IsUnavailable(err error) bool {
+	return IsRetryable(err) || errors.Is(err, ErrCircuitOpen)
+}
+
+// Failover 按顺序查询一组数据源，当前数据源不可用时切换到下一个，之后的查询继续使用切换后的数据源，
+// 同一台主机的数据尽量来自同一个数据源
+type Failover struct {
+	mu      sync.Mutex
+	sources []*Source
+	current int
+}
+
+// NewFailover 创建故障转移查询器，主数据源排在备用数据源之前，同一角色保持原有顺序
+func NewFailover(sources ...*Source) *Failover {
+	ordered := make([]*Source, 0, len(sources))
+	for _, fallback := range []bool{false, true} {
+		for _, source := range sources {
+			if source.Fallback == fallback {
+				ordered = append(ordered, source)
+			}
+		}
+	}
+	return &Failover{sources: ordered}
+}
+
+// Source 返回当前使用的数据源名称，没有数据源时返回空字符串
+func (f *Failover) Source() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.current >= len(f.sources) {
+		return ""
+	}
+	return f.sources[f.current].Name
+}
+
+// Query 在当前数据源上执行即时查询，数据源不可用时切换到下一个
+func (f *Failover) Query(ctx context.Context, query string, timestamp time.Time) (*QueryResult, error) {
+	return f.do(func(client *Client) (*QueryResult, error) {
+		return client.Query(ctx, query, timestamp)
+	})
+}
+
+// QueryRange 在当前数据源上执行范围查询，数据源不可用时切换到下一个
+func (f *Failover) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*QueryRangeResult, error) {
+	return f.do(func(client *Client) (*QueryRangeResult, error) {
+		return client.QueryRange(ctx, query, start, end, step)
+	})
+}
+
+// do 从当前数据源开始依次查询，直到成功、遇到数据源可用时的错误或没有更多数据源。
+// 结果为空时继续查询同一角色的下一个数据源，使手动列出的主机也能在按地域分片的数据源中找到；
+// 所有同一角色的数据源都返回空结果时，返回第一个空结果
+func (f *Failover) do(query func(client *Client) (*QueryResult, error)) (*QueryResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.sources) == 0 {
+		return nil, fmt.Errorf("no data source available")
+	}
+
+	var (
+		errs       []error
+		empty      *QueryResult
+		emptyIndex int
+	)
+	for index := f.current; index < len(f.sources); index++ {
+		source := f.sources[index]
+		// 已有空结果且没有数据源不可用时，不再查询其他角色的数据源
+		if empty != nil && len(errs) == 0 && source.Fallback != f.sources[emptyIndex].Fallback {
+			break
+		}
+
+		result, err := query(source.Client)
+		switch {
+		case err == nil && result.Empty():
+			if empty == nil {
+				empty, emptyIndex = result, index
+			}
+		case err == nil || !IsUnavailable(err):
+			f.current = index
+			return result, err
+		default:
+			errs = append(errs, fmt.Errorf("data source %s: %w", source.Name, err))
+		}
+	}
+	if empty != nil {
+		f.current = emptyIndex
+		return empty, nil
+	}
+
+	// 所有数据源都不可用时，下次查询重新从主数据源开始
+	f.current = 0
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Failover", func() {
+	// source 创建一个数据源，status不为200时返回该状态码
+	source := func(name string, fallback bool, status *atomic.Int32, requests *atomic.Int32) *Source {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if code := int(status.Load()); code != http.StatusOK {
+				w.WriteHeader(code)
+				return
+			}
+			_, _ = w.Write([]byte(vectorResponse))
+		}))
+		DeferCleanup(server.Close)
+
+		client := NewClient(server.URL)
+		client.Retry = RetryPolicy{MaxAttempts: 1}
+		client.Breaker = NewCircuitBreaker(DefaultBreakerThreshold, time.Minute)
+		return &Source{Name: name, Fallback: fallback, Client: client}
+	}
+
+	var (
+		primaryStatus, fallbackStatus     atomic.Int32
+		primaryRequests, fallbackRequests atomic.Int32
+		failover                          *Failover
+	)
+
+	BeforeEach(func() {
+		primaryStatus.Store(http.StatusOK)
+		fallbackStatus.Store(http.StatusOK)
+		primaryRequests.Store(0)
+		fallbackRequests.Store(0)
+		// 备用数据源排在前面时仍然先查询主数据源
+		failover = NewFailover(
+			source("replica", true, &fallbackStatus, &fallbackRequests),
+			source("primary", false, &primaryStatus, &primaryRequests),
+		)
+	})
+
+	It("should query the primary data source first", func() {
+		_, err := failover.Query(context.Background(), "up", time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failover.Source()).To(Equal("primary"))
+		Expect(fallbackRequests.Load()).To(BeZero())
+	})
+
+	It("should stay on the fallback once the primary is unavailable", func() {
+		primaryStatus.Store(http.StatusServiceUnavailable)
+		_, err := failover.Query(context.Background(), "up", time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failover.Source()).To(Equal("replica"))
+
+		_, err = failover.QueryRange(context.Background(), "up", time.Now().Add(-time.Hour), time.Now(), time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(primaryRequests.Load()).To(Equal(int32(1)))
+		Expect(fallbackRequests.Load()).To(Equal(int32(2)))
+	})
+
+	It("should not fail over on query errors", func() {
+		primaryStatus.Store(http.StatusBadRequest)
+		_, err := failover.Query(context.Background(), "up", time.Time{})
+		Expect(err).To(HaveOccurred())
+		Expect(failover.Source()).To(Equal("primary"))
+		Expect(fallbackRequests.Load()).To(BeZero())
+	})
+
+	It("should report every data source when all are unavailable", func() {
+		primaryStatus.Store(http.StatusBadGateway)
+		fallbackStatus.Store(http.StatusServiceUnavailable)
+		_, err := failover.Query(context.Background(), "up", time.Time{})
+		Expect(err).To(MatchError(ContainSubstring("data source primary")))
+		Expect(err).To(MatchError(ContainSubstring("data source replica")))
+		Expect(failover.Source()).To(Equal("primary"))
+	})
+
+	Context("when a data source returns no series", func() {
+		// shard 创建一个数据源，empty为true时返回空结果
+		shard := func(name string, fallback bool, empty *atomic.Bool, requests *atomic.Int32) *Source {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if empty.Load() {
+					_, _ = w.Write([]byte(emptyVectorResponse))
+					return
+				}
+				_, _ = w.Write([]byte(vectorResponse))
+			}))
+			DeferCleanup(server.Close)
+
+			client := NewClient(server.URL)
+			client.Retry = RetryPolicy{MaxAttempts: 1}
+			return &Source{Name: name, Fallback: fallback, Client: client}
+		}
+
+		var (
+			firstEmpty, secondEmpty, replicaEmpty          atomic.Bool
+			firstRequests, secondRequests, replicaRequests atomic.Int32
+		)
+
+		BeforeEach(func() {
+			firstEmpty.Store(true)
+			secondEmpty.Store(false)
+			replicaEmpty.Store(false)
+			firstRequests.Store(0)
+			secondRequests.Store(0)
+			replicaRequests.Store(0)
+			failover = NewFailover(
+				shard("shard-a", false, &firstEmpty, &firstRequests),
+				shard("shard-b", false, &secondEmpty, &secondRequests),
+				shard("replica", true, &replicaEmpty, &replicaRequests),
+			)
+		})
+
+		It("should try the next primary data source and stay on it", func() {
+			result, err := failover.Query(context.Background(), "up", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Data.Result).To(HaveLen(1))
+			Expect(failover.Source()).To(Equal("shard-b"))
+
+			_, err = failover.Query(context.Background(), "node_load1", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(firstRequests.Load()).To(Equal(int32(1)))
+			Expect(secondRequests.Load()).To(Equal(int32(2)))
+		})
+
+		It("should return the first empty result without querying the fallback", func() {
+			secondEmpty.Store(true)
+			result, err := failover.Query(context.Background(), "up", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Empty()).To(BeTrue())
+			Expect(failover.Source()).To(Equal("shard-a"))
+			Expect(secondRequests.Load()).To(Equal(int32(1)))
+			Expect(replicaRequests.Load()).To(BeZero())
+		})
+
+		It("should not go back to an earlier primary data source", func() {
+			_, err := failover.Query(context.Background(), "up", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+
+			secondEmpty.Store(true)
+			result, err := failover.Query(context.Background(), "node_load1", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Empty()).To(BeTrue())
+			Expect(failover.Source()).To(Equal("shard-b"))
+			Expect(firstRequests.Load()).To(Equal(int32(1)))
+		})
+	})
+})
@@ -0,0 +1,243 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// 查询结果类型
+const (
+	ResultVector = "vector"
+	ResultMatrix = "matrix"
+	ResultScalar = "scalar"
+	ResultString = "string"
+)
+
+// ErrNoResults 查询没有返回结果，通常是主机没有对应的指标
+var ErrNoResults = errors.New("no results returned")
+
+// ErrNonFinite 查询结果为NaN或±Inf，如分母为0的比值，不能作为使用率
+var ErrNonFinite = errors.New("non-finite sample value")
+
+// ErrMultipleSeries 按主机查询时返回了多条时间序列，通常是同一主机被多个抓取任务采集，
+// 只取其中一条会得到不确定的结果
+var ErrMultipleSeries = errors.New("query matched multiple series")
+
+// Series 即时查询或范围查询返回的一条时间序列
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	// vector结果的采样点，[时间戳, 值]
+	Value []interface{} `json:"value,omitempty"`
+	// matrix结果的采样点
+	Values [][]interface{} `json:"values,omitempty"`
+}
+
+// QueryData 查询结果，按resultType解析result
+type QueryData struct {
+	ResultType string `json:"resultType"`
+	// vector和matrix结果
+	Result []Series `json:"-"`
+	// scalar和string结果，[时间戳, 值]
+	Scalar []interface{} `json:"-"`
+}
+
+// UnmarshalJSON 按resultType解析result字段
+func (d *QueryData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	d.ResultType = raw.ResultType
+	switch raw.ResultType {
+	case ResultVector, ResultMatrix:
+		return json.Unmarshal(raw.Result, &d.Result)
+	case ResultScalar, ResultString:
+		return json.Unmarshal(raw.Result, &d.Scalar)
+	case "":
+		// 查询失败时没有data
+		return nil
+	}
+	return fmt.Errorf("unknown result type %q", raw.ResultType)
+}
+
+// QueryResult Prometheus HTTP API的响应
+type QueryResult struct {
+	// success或error
+	Status string `json:"status"`
+	// 查询失败时的错误类型和错误信息，如bad_data、timeout、execution
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// 查询成功但结果可能不完整时的警告，如部分存储节点不可用
+	Warnings []string  `json:"warnings,omitempty"`
+	Data     QueryData `json:"data"`
+}
+
+// Empty 结果是否为不包含任何时间序列的vector或matrix
+func (r *QueryResult) Empty() bool {
+	switch r.Data.ResultType {
+	case ResultVector, ResultMatrix:
+		return len(r.Data.Result) == 0
+	}
+	return false
+}
+
+// QueryRangeResult 范围查询的响应，与即时查询的结构相同
+type QueryRangeResult = QueryResult
+
+// ParseFloat 解析采样值，支持Prometheus使用的NaN、+Inf和-Inf
+func ParseFloat(value interface{}) (float64, error) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("failed to parse value: %v", value)
+	}
+	switch str {
+	case "NaN":
+		return math.NaN(), nil
+	case "+Inf", "Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
+// finite 值是否为有限数
+func finite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// parsePair 解析[时间戳, 值]形式的采样点
+func parsePair(pair []interface{}) (time.Time, float64, error) {
+	if len(pair) != 2 {
+		return time.Time{}, 0, fmt.Errorf("failed to parse sample: %v", pair)
+	}
+	timestamp, ok := pair[0].(float64)
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("failed to parse timestamp: %v", pair[0])
+	}
+	value, err := ParseFloat(pair[1])
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return time.Unix(0, int64(timestamp*float64(time.Second))), value, nil
+}
+
+// ParseValue 从即时查询结果中解析单个数值，支持vector和scalar结果。
+// vector结果包含多条时间序列时返回ErrMultipleSeries，值为NaN或±Inf时返回ErrNonFinite
+func ParseValue(result *QueryResult) (float64, error) {
+	if result == nil {
+		return 0, ErrNoResults
+	}
+
+	var pair []interface{}
+	switch result.Data.ResultType {
+	case ResultScalar:
+		pair = result.Data.Scalar
+	case ResultVector:
+		switch len(result.Data.Result) {
+		case 0:
+			return 0, ErrNoResults
+		case 1:
+			pair = result.Data.Result[0].Value
+		default:
+			return 0, fmt.Errorf("%w: %d series", ErrMultipleSeries, len(result.Data.Result))
+		}
+	default:
+		return 0, fmt.Errorf("unexpected result type %q", result.Data.ResultType)
+	}
+
+	_, value, err := parsePair(pair)
+	if err != nil {
+		return 0, err
+	}
+	if !finite(value) {
+		return 0, fmt.Errorf("%w: %v", ErrNonFinite, value)
+	}
+	return value, nil
+}
+
+// ParseString 从即时查询结果中解析字符串
+func ParseString(result *QueryResult) (string, error) {
+	if result == nil || result.Data.ResultType != ResultString || len(result.Data.Scalar) != 2 {
+		return "", fmt.Errorf("no string result returned")
+	}
+	value, ok := result.Data.Scalar[1].(string)
+	if !ok {
+		return "", fmt.Errorf("failed to parse value: %v", result.Data.Scalar[1])
+	}
+	return value, nil
+}
+
+// Sample 范围查询返回的一个采样点
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// ParseSeries 从范围查询结果中解析唯一的一条时间序列，返回多条时返回ErrMultipleSeries。
+// 值为NaN或±Inf的采样点被跳过，在趋势和基线中视为缺失
+func ParseSeries(result *QueryRangeResult) ([]Sample, error) {
+	if result == nil || len(result.Data.Result) == 0 {
+		return nil, ErrNoResults
+	}
+	if result.Data.ResultType != ResultMatrix {
+		return nil, fmt.Errorf("unexpected result type %q", result.Data.ResultType)
+	}
+	if len(result.Data.Result) > 1 {
+		return nil, fmt.Errorf("%w: %d series", ErrMultipleSeries, len(result.Data.Result))
+	}
+
+	values := result.Data.Result[0].Values
+	samples := make([]Sample, 0, len(values))
+	for _, pair := range values {
+		timestamp, value, err := parsePair(pair)
+		if err != nil {
+			return nil, err
+		}
+		if !finite(value) {
+			continue
+		}
+		samples = append(samples, Sample{Time: timestamp, Value: value})
+	}
+
+	return samples, nil
+}
+
+// VectorSample 即时查询返回的一条时间序列
+type VectorSample struct {
+	Metric map[string]string
+	Value  float64
+}
+
+// ParseVector 解析即时查询返回的所有时间序列，没有结果时返回空切片。
+// 值为NaN或±Inf的时间序列被跳过，如容量为0的分区
+func ParseVector(result *QueryResult) ([]VectorSample, error) {
+	if result == nil {
+		return nil, ErrNoResults
+	}
+	if result.Data.ResultType != ResultVector {
+		return nil, fmt.Errorf("unexpected result type %q", result.Data.ResultType)
+	}
+
+	samples := make([]VectorSample, 0, len(result.Data.Result))
+	for _, item := range result.Data.Result {
+		_, value, err := parsePair(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !finite(value) {
+			continue
+		}
+		samples = append(samples, VectorSample{Metric: item.Metric, Value: value})
+	}
+
+	return samples, nil
+}
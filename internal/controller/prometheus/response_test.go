@@ -0,0 +1,74 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// decode 解析Prometheus API的响应
+func decode(body string) *QueryResult {
+	var result QueryResult
+	ExpectWithOffset(1, json.Unmarshal([]byte(body), &result)).To(Succeed())
+	return &result
+}
+
+var _ = Describe("Response", func() {
+	It("should parse scalar results", func() {
+		value, err := ParseValue(decode(`{"status":"success","data":{"resultType":"scalar","result":[1700000000,"0.5"]}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(0.5))
+	})
+
+	It("should parse string results", func() {
+		value, err := ParseString(decode(`{"status":"success","data":{"resultType":"string","result":[1700000000,"v2.53.0"]}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("v2.53.0"))
+	})
+
+	It("should reject vectors with multiple series", func() {
+		_, err := ParseValue(decode(`{"status":"success","data":{"resultType":"vector","result":[` +
+			`{"metric":{"job":"node"},"value":[1700000000,"1"]},{"metric":{"job":"node-dup"},"value":[1700000000,"2"]}]}}`))
+		Expect(err).To(MatchError(ErrMultipleSeries))
+	})
+
+	It("should reject non-finite values", func() {
+		for _, value := range []string{"NaN", "+Inf", "-Inf"} {
+			_, err := ParseValue(decode(`{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+			Expect(err).To(MatchError(ErrNonFinite), value)
+		}
+	})
+
+	It("should parse special float values", func() {
+		Expect(ParseFloat("NaN")).To(Satisfy(math.IsNaN))
+		Expect(ParseFloat("+Inf")).To(Equal(math.Inf(1)))
+		Expect(ParseFloat("-Inf")).To(Equal(math.Inf(-1)))
+		_, err := ParseFloat("1e3x")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should skip non-finite points in matrix results", func() {
+		samples, err := ParseSeries(decode(`{"status":"success","data":{"resultType":"matrix","result":[` +
+			`{"metric":{},"values":[[1700000000,"1"],[1700000060,"NaN"],[1700000120,"3"]]}]}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(2))
+		Expect(samples[1].Value).To(Equal(3.0))
+	})
+
+	It("should skip non-finite series in vector results", func() {
+		samples, err := ParseVector(decode(`{"status":"success","data":{"resultType":"vector","result":[` +
+			`{"metric":{"mountpoint":"/"},"value":[1700000000,"12"]},{"metric":{"mountpoint":"/empty"},"value":[1700000000,"NaN"]}]}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(Equal([]VectorSample{{Metric: map[string]string{"mountpoint": "/"}, Value: 12}}))
+	})
+
+	It("should reject unexpected result types", func() {
+		_, err := ParseSeries(decode(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"1"]}]}}`))
+		Expect(err).To(HaveOccurred())
+		var result QueryResult
+		Expect(json.Unmarshal([]byte(`{"status":"success","data":{"resultType":"histogram","result":[]}}`), &result)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,115 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 重试的默认配置
+const (
+	// DefaultMaxAttempts 每次查询最多请求的次数，包括第一次请求
+	DefaultMaxAttempts = 3
+	// DefaultInitialBackoff 第一次重试前的等待时间
+	DefaultInitialBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff 两次请求之间最长的等待时间，Retry-After超过该值时不再重试
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// RetryPolicy 查询失败时的重试策略，只对可重试的临时错误生效
+type RetryPolicy struct {
+	// 最多请求的次数，为1时不重试
+	MaxAttempts int
+	// 第一次重试前的等待时间，之后每次翻倍
+	InitialBackoff time.Duration
+	// 两次请求之间最长的等待时间
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy 返回默认的重试策略
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// backoff 返回第attempt次请求失败后的等待时间，按指数增长并加入最多50%的随机抖动，
+// 避免多个巡检同时重试。服务端通过Retry-After指定了等待时间时以其为准
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(2, float64(attempt-1))
+	wait = math.Min(wait, float64(p.MaxBackoff))
+	wait = wait/2 + rand.Float64()*wait/2
+	return time.Duration(wait)
+}
+
+// do 按重试策略执行请求，每次请求前检查熔断器
+func (c *Client) do(ctx context.Context, request func(ctx context.Context) error) error {
+	policy := c.Retry
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.attempt(ctx, request)
+		if !IsRetryable(err) || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		var retryAfter time.Duration
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			retryAfter = statusErr.RetryAfter
+		}
+		if retryAfter > policy.MaxBackoff {
+			return err
+		}
+		wait := policy.backoff(attempt, retryAfter)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.String("wait", wait.String()),
+			attribute.String("error", err.Error()),
+		))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt 执行一次请求，并将结果记录到熔断器
+func (c *Client) attempt(ctx context.Context, request func(ctx context.Context) error) error {
+	breaker := c.breaker()
+	if breaker == nil {
+		return request(ctx)
+	}
+	if err := breaker.Allow(); err != nil {
+		return err
+	}
+	err := request(ctx)
+	if ctx.Err() != nil {
+		// 请求被取消或超过巡检的截止时间，无法判断Prometheus是否正常，不计入熔断并释放试探名额
+		breaker.Release()
+		return err
+	}
+	// 永久错误说明Prometheus可以正常响应，不计入熔断
+	breaker.Record(!IsRetryable(err))
+	return err
+}
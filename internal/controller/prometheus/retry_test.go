@@ -0,0 +1,234 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// vectorResponse 返回单条时间序列的即时查询结果
+const vectorResponse = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"1"]}]}}`
+
+// emptyVectorResponse 不包含时间序列的即时查询结果
+const emptyVectorResponse = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+
+var _ = Describe("Retries", func() {
+	var (
+		requests  atomic.Int32
+		responses []func(w http.ResponseWriter)
+		server    *httptest.Server
+		client    *Client
+	)
+
+	ok := func(w http.ResponseWriter) { _, _ = w.Write([]byte(vectorResponse)) }
+	status := func(code int, header ...string) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			if len(header) == 2 {
+				w.Header().Set(header[0], header[1])
+			}
+			w.WriteHeader(code)
+		}
+	}
+
+	BeforeEach(func() {
+		requests.Store(0)
+		responses = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := int(requests.Add(1)) - 1
+			if n >= len(responses) {
+				n = len(responses) - 1
+			}
+			responses[n](w)
+		}))
+		DeferCleanup(server.Close)
+
+		client = NewClient(server.URL)
+		client.Retry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+		client.Breaker = NewCircuitBreaker(DefaultBreakerThreshold, time.Minute)
+	})
+
+	It("should retry transient failures", func() {
+		responses = append(responses, status(http.StatusServiceUnavailable), status(http.StatusBadGateway), ok)
+		_, err := client.Query(context.Background(), "up", time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests.Load()).To(Equal(int32(3)))
+	})
+
+	It("should give up after the maximum number of attempts", func() {
+		responses = append(responses, status(http.StatusServiceUnavailable))
+		_, err := client.Query(context.Background(), "up", time.Time{})
+		var statusErr *StatusError
+		Expect(errors.As(err, &statusErr)).To(BeTrue())
+		Expect(statusErr.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(requests.Load()).To(Equal(int32(3)))
+	})
+
+	It("should not retry permanent failures", func() {
+		responses = append(responses, status(http.StatusBadRequest))
+		_, err := client.QueryRange(context.Background(), "up", time.Now().Add(-time.Hour), time.Now(), time.Minute)
+		Expect(err).To(HaveOccurred())
+		Expect(IsRetryable(err)).To(BeFalse())
+		Expect(requests.Load()).To(Equal(int32(1)))
+	})
+
+	It("should wait for Retry-After", func() {
+		client.Retry.MaxBackoff = 2 * time.Second
+		responses = append(responses, status(http.StatusTooManyRequests, "Retry-After", "1"), ok)
+
+		start := time.Now()
+		_, err := client.Query(context.Background(), "up", time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+	})
+
+	It("should not wait longer than the maximum backoff", func() {
+		responses = append(responses, status(http.StatusTooManyRequests, "Retry-After", "120"), ok)
+		_, err := client.Query(context.Background(), "up", time.Time{})
+		Expect(err).To(HaveOccurred())
+		Expect(requests.Load()).To(Equal(int32(1)))
+	})
+
+	It("should fail fast while the circuit breaker is open", func() {
+		client.Retry.MaxAttempts = 1
+		responses = append(responses, status(http.StatusServiceUnavailable))
+		for i := 0; i < DefaultBreakerThreshold; i++ {
+			_, err := client.Query(context.Background(), "up", time.Time{})
+			Expect(err).To(HaveOccurred())
+		}
+
+		_, err := client.Query(context.Background(), "up", time.Time{})
+		Expect(err).To(MatchError(ErrCircuitOpen))
+		Expect(requests.Load()).To(Equal(int32(DefaultBreakerThreshold)))
+	})
+
+	It("should not record cancelled requests in the circuit breaker", func() {
+		now := time.Now()
+		client.Breaker.now = func() time.Time { return now }
+		for range DefaultBreakerThreshold {
+			client.Breaker.Record(false)
+		}
+		now = now.Add(2 * time.Minute)
+
+		// 试探请求被取消后熔断器保持打开，下一个请求可以立即重新试探
+		ctx, cancel := context.WithCancel(context.Background())
+		responses = append(responses, func(w http.ResponseWriter) {
+			// 等待客户端放弃请求后再响应
+			cancel()
+			time.Sleep(50 * time.Millisecond)
+			ok(w)
+		})
+		_, err := client.Query(ctx, "up", time.Time{})
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(client.Breaker.Open()).To(BeTrue())
+
+		responses = append(responses, ok)
+		_, err = client.Query(context.Background(), "up", time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Breaker.Open()).To(BeFalse())
+	})
+})
+
+var _ = Describe("CircuitBreaker", func() {
+	It("should allow a single probe after the cooldown", func() {
+		now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+		breaker := NewCircuitBreaker(2, time.Minute)
+		breaker.now = func() time.Time { return now }
+
+		breaker.Record(false)
+		Expect(breaker.Open()).To(BeFalse())
+		breaker.Record(false)
+		Expect(breaker.Allow()).To(MatchError(ErrCircuitOpen))
+
+		now = now.Add(time.Minute)
+		Expect(breaker.Allow()).To(Succeed())
+		Expect(breaker.Allow()).To(MatchError(ErrCircuitOpen))
+
+		breaker.Record(false)
+		Expect(breaker.Allow()).To(MatchError(ErrCircuitOpen))
+
+		now = now.Add(time.Minute)
+		Expect(breaker.Allow()).To(Succeed())
+		breaker.Record(true)
+		Expect(breaker.Open()).To(BeFalse())
+		Expect(breaker.Allow()).To(Succeed())
+	})
+
+	It("should share breakers by resolved endpoint and tenant", func() {
+		// newClient 创建使用共享熔断器的客户端，Backend在创建后设置
+		newClient := func(url string, options BackendOptions) *Client {
+			client := NewClient(url)
+			client.Backend = options
+			return client
+		}
+		mimir := BackendOptions{Type: BackendMimir, Tenant: "team-a"}
+
+		Expect(newClient("http://mimir:8080/", mimir).breaker()).
+			To(BeIdenticalTo(newClient("http://mimir:8080", mimir).breaker()))
+		Expect(newClient("http://mimir:8080", mimir).breaker()).
+			NotTo(BeIdenticalTo(newClient("http://mimir:8080", BackendOptions{Type: BackendMimir, Tenant: "team-b"}).breaker()))
+		Expect(newClient("http://vmselect:8481", BackendOptions{Type: BackendVictoriaMetrics, Tenant: "0"}).breaker()).
+			NotTo(BeIdenticalTo(newClient("http://vmselect:8481", BackendOptions{Type: BackendVictoriaMetrics, PathPrefix: "/select/1/prometheus"}).breaker()))
+		Expect(newClient("http://prometheus:9090", BackendOptions{}).breaker()).
+			NotTo(BeIdenticalTo(newClient("http://prometheus:9090", mimir).breaker()))
+	})
+
+	It("should keep other tenants available while one tenant is failing", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(headerMimirTenant) == "team-a" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(vectorResponse))
+		}))
+		DeferCleanup(server.Close)
+
+		tenant := func(name string) *Client {
+			client := NewClient(server.URL)
+			client.Retry = RetryPolicy{MaxAttempts: 1}
+			client.Backend = BackendOptions{Type: BackendMimir, Tenant: name}
+			return client
+		}
+		failing, healthy := tenant("team-a"), tenant("team-b")
+		for range DefaultBreakerThreshold {
+			_, err := failing.Query(context.Background(), "up", time.Time{})
+			Expect(err).To(HaveOccurred())
+		}
+		_, err := failing.Query(context.Background(), "up", time.Time{})
+		Expect(err).To(MatchError(ErrCircuitOpen))
+
+		_, err = healthy.Query(context.Background(), "up", time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("Error classification", func() {
+	DescribeTable("IsRetryable",
+		func(err error, retryable bool) {
+			Expect(IsRetryable(err)).To(Equal(retryable))
+		},
+		Entry("nil", nil, false),
+		Entry("429", &StatusError{StatusCode: http.StatusTooManyRequests}, true),
+		Entry("503", &StatusError{StatusCode: http.StatusServiceUnavailable}, true),
+		Entry("501", &StatusError{StatusCode: http.StatusNotImplemented}, false),
+		Entry("422", &StatusError{StatusCode: http.StatusUnprocessableEntity}, false),
+		Entry("wrapped 502", fmt.Errorf("查询失败: %w", &StatusError{StatusCode: http.StatusBadGateway}), true),
+		Entry("query timeout", &StatusError{StatusCode: http.StatusServiceUnavailable, ErrorType: ErrorTypeTimeout}, true),
+		Entry("bad data", &StatusError{StatusCode: http.StatusBadRequest, ErrorType: "bad_data"}, false),
+		Entry("circuit open", ErrCircuitOpen, false),
+		Entry("canceled", context.Canceled, false),
+	)
+
+	It("should parse Retry-After as seconds or an HTTP date", func() {
+		now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+		Expect(parseRetryAfter("5", now)).To(Equal(5 * time.Second))
+		Expect(parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)).To(Equal(time.Minute))
+		Expect(parseRetryAfter("soon", now)).To(BeZero())
+	})
+})
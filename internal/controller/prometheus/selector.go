@@ -0,0 +1,123 @@
+package prometheus
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MatchType 标签匹配方式
+type MatchType string
+
+const (
+	MatchEqual    MatchType = "="
+	MatchNotEqual MatchType = "!="
+	MatchRegex    MatchType = "=~"
+	MatchNotRegex MatchType = "!~"
+)
+
+// labelNamePattern Prometheus标签名称的格式
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Matcher 标签匹配器，值在生成选择器时转义
+type Matcher struct {
+	Name  string
+	Type  MatchType
+	Value string
+}
+
+// NewMatcher 创建标签匹配器，校验标签名称，正则匹配时校验正则表达式
+func NewMatcher(name string, matchType MatchType, value string) (Matcher, error) {
+	if !labelNamePattern.MatchString(name) {
+		return Matcher{}, fmt.Errorf("invalid label name %q", name)
+	}
+	switch matchType {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegex, MatchNotRegex:
+		// Prometheus的正则表达式始终完整匹配标签值
+		if _, err := regexp.Compile("^(?:" + value + ")$"); err != nil {
+			return Matcher{}, fmt.Errorf("invalid regular expression for label %q: %w", name, err)
+		}
+	default:
+		return Matcher{}, fmt.Errorf("invalid match type %q for label %q", matchType, name)
+	}
+	return Matcher{Name: name, Type: matchType, Value: value}, nil
+}
+
+// String 返回PromQL形式的匹配器，如 env!="prod"
+func (m Matcher) String() string {
+	return m.Name + string(m.Type) + strconv.Quote(m.Value)
+}
+
+// matchesEmpty 匹配器是否匹配空值，即是否会选中不带该标签的时间序列
+func (m Matcher) matchesEmpty() bool {
+	switch m.Type {
+	case MatchEqual:
+		return m.Value == ""
+	case MatchNotEqual:
+		return m.Value != ""
+	}
+	matched := regexp.MustCompile("^(?:" + m.Value + ")$").MatchString("")
+	return matched == (m.Type == MatchRegex)
+}
+
+// ParseMatchers 解析主机标签配置，值以!=、=~、!~开头时按对应方式匹配，否则按相等匹配，
+// 如 env: "!=prod"、role: "=~web|api"
+func ParseMatchers(labels map[string]string) ([]Matcher, error) {
+	matchers := make([]Matcher, 0, len(labels))
+	for name, value := range labels {
+		matchType := MatchEqual
+		for _, prefix := range []MatchType{MatchNotEqual, MatchRegex, MatchNotRegex} {
+			if strings.HasPrefix(value, string(prefix)) {
+				matchType, value = prefix, strings.TrimPrefix(value, string(prefix))
+				break
+			}
+		}
+		matcher, err := NewMatcher(name, matchType, value)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	return Selector(matchers).sorted(), nil
+}
+
+// Selector 标签选择器，生成的PromQL按标签名称、匹配方式和值排序，相同的配置始终得到相同的查询
+type Selector []Matcher
+
+// sorted 返回排序后的副本
+func (s Selector) sorted() Selector {
+	sorted := slices.Clone(s)
+	slices.SortFunc(sorted, func(a, b Matcher) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Type, b.Type), cmp.Compare(a.Value, b.Value))
+	})
+	return sorted
+}
+
+// String 返回不含花括号的选择器，如 env!="prod",instance="10.0.0.1:9100"
+func (s Selector) String() string {
+	conditions := make([]string, 0, len(s))
+	for _, matcher := range s.sorted() {
+		conditions = append(conditions, matcher.String())
+	}
+	return strings.Join(conditions, ",")
+}
+
+// Selective 选择器是否至少包含一个不匹配空值的条件。
+// 只有否定条件的选择器会选中所有不带这些标签的抓取目标，不能用于发现主机
+func (s Selector) Selective() bool {
+	return slices.ContainsFunc(s, func(m Matcher) bool { return !m.matchesEmpty() })
+}
+
+// nodeSelector 构建单台主机的选择器，instance为空时只使用标签匹配器
+func nodeSelector(instance string, matchers []Matcher, extra ...Matcher) Selector {
+	selector := make(Selector, 0, len(matchers)+len(extra)+1)
+	if instance != "" {
+		selector = append(selector, Matcher{Name: "instance", Type: MatchEqual, Value: instance})
+	}
+	selector = append(selector, matchers...)
+	return append(selector, extra...)
+}
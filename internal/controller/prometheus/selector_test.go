@@ -0,0 +1,80 @@
+package prometheus
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Selector", func() {
+	It("should parse matcher prefixes", func() {
+		matchers, err := ParseMatchers(map[string]string{
+			"role":     "=~web|api",
+			"env":      "!=prod",
+			"business": "devops",
+			"zone":     "!~cn-north-.*",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Selector(matchers).String()).To(Equal(
+			`business="devops",env!="prod",role=~"web|api",zone!~"cn-north-.*"`))
+	})
+
+	It("should escape label values", func() {
+		matchers, err := ParseMatchers(map[string]string{"team": `ops"}` + "\\"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NodeUpQuery(`10.0.0.1:9100"`, matchers)).To(Equal(`up{instance="10.0.0.1:9100\"",team="ops\"}\\"}`))
+	})
+
+	It("should produce the same query regardless of map order", func() {
+		labels := map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5"}
+		first, err := ParseMatchers(labels)
+		Expect(err).NotTo(HaveOccurred())
+		expected := CPUUsageQuery("10.0.0.1:9100", first)
+		Expect(expected).To(ContainSubstring(`{a="1",b="2",c="3",d="4",e="5",instance="10.0.0.1:9100",mode="idle"}`))
+		for range 20 {
+			matchers, err := ParseMatchers(labels)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(CPUUsageQuery("10.0.0.1:9100", matchers)).To(Equal(expected))
+		}
+	})
+
+	It("should keep the filesystem filters", func() {
+		query := FilesystemUsageQuery("10.0.0.1:9100", nil, FilesystemFilter{ExcludeMountpoints: []string{"/boot.*"}})
+		Expect(query).To(ContainSubstring(`{fstype!~"tmpfs|rootfs",instance="10.0.0.1:9100",mountpoint!~"/boot.*"}`))
+	})
+
+	DescribeTable("should require a matcher that does not match the empty value",
+		func(labels map[string]string, selective bool) {
+			matchers, err := ParseMatchers(labels)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(Selector(matchers).Selective()).To(Equal(selective))
+		},
+		Entry("equality", map[string]string{"env": "prod"}, true),
+		Entry("empty equality", map[string]string{"env": ""}, false),
+		Entry("negative matchers only", map[string]string{"env": "!=prod", "zone": "!~cn-north-.*"}, false),
+		Entry("label must be present", map[string]string{"env": "!="}, true),
+		Entry("regular expression", map[string]string{"role": "=~web|api"}, true),
+		Entry("regular expression matching the empty value", map[string]string{"role": "=~.*"}, false),
+		Entry("negative regular expression excluding the empty value", map[string]string{"role": "!~"}, true),
+		Entry("negative and positive matchers", map[string]string{"env": "!=prod", "role": "web"}, true),
+	)
+
+	It("should not discover nodes with negative matchers only", func() {
+		matchers, err := ParseMatchers(map[string]string{"env": "!=prod"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = NewClient("http://127.0.0.1:0").GetNodesByLabels(context.Background(), matchers)
+		Expect(err).To(MatchError(ContainSubstring("不匹配空值")))
+	})
+
+	DescribeTable("should reject invalid matchers",
+		func(labels map[string]string) {
+			_, err := ParseMatchers(labels)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("label name with a dash", map[string]string{"app-name": "web"}),
+		Entry("label name starting with a digit", map[string]string{"1env": "prod"}),
+		Entry("injected selector", map[string]string{`env="prod"} or up{job`: "node"}),
+		Entry("invalid regular expression", map[string]string{"role": "=~web("}),
+	)
+})
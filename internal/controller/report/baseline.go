@@ -0,0 +1,159 @@
+package report
+
+import (
+	"math"
+	"time"
+)
+
+// 动态基线的默认配置
+const (
+	// DefaultBaselineDays 计算基线使用的历史天数
+	DefaultBaselineDays = 7
+	// DefaultBaselineSigma 偏离均值超过该倍数的标准差时标记为异常
+	DefaultBaselineSigma = 3
+	// DefaultBaselineStep 范围查询的采样间隔
+	DefaultBaselineStep = time.Hour
+	// MinBaselineStddev 标准差的下限（百分点），避免长期平稳的主机出现极小的波动也被标记为异常
+	MinBaselineStddev = 1
+	// MinBaselineSamples 采样点少于该数量时数据不足，不做基线检查
+	MinBaselineSamples = 12
+)
+
+// BaselineConfig 动态基线配置，为nil时报告中不展示基线
+type BaselineConfig struct {
+	// 计算基线使用的历史天数
+	Days int
+	// 偏离均值超过Sigma倍标准差时标记为异常
+	Sigma float64
+	// 范围查询的采样间隔
+	Step time.Duration
+}
+
+// Baseline 主机单项指标在历史窗口内的基线
+type Baseline struct {
+	// 均值和标准差（百分比）
+	Mean   float64
+	Stddev float64
+	// 参与计算的采样点数
+	Samples int
+	// 当前值偏离均值的标准差倍数，带正负号
+	Deviation float64
+	// 偏离超过阈值时为1
+	Status int
+}
+
+// NewBaseline 根据历史采样点计算均值和标准差
+func NewBaseline(values []float64) Baseline {
+	baseline := Baseline{Samples: len(values)}
+	if len(values) == 0 {
+		return baseline
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	baseline.Mean = sum / float64(len(values))
+
+	var variance float64
+	for _, value := range values {
+		variance += (value - baseline.Mean) * (value - baseline.Mean)
+	}
+	baseline.Stddev = math.Round(math.Sqrt(variance/float64(len(values)))*100) / 100
+	baseline.Mean = math.Round(baseline.Mean*100) / 100
+	return baseline
+}
+
+// Lower 返回基线允许的下限
+func (b Baseline) Lower(sigma float64) float64 {
+	return math.Round(math.Max(b.Mean-sigma*b.stddev(), 0)*100) / 100
+}
+
+// Upper 返回基线允许的上限
+func (b Baseline) Upper(sigma float64) float64 {
+	return math.Round(math.Min(b.Mean+sigma*b.stddev(), 100)*100) / 100
+}
+
+// stddev 返回计算偏离程度时使用的标准差，不低于MinBaselineStddev
+func (b Baseline) stddev() float64 {
+	return math.Max(b.Stddev, MinBaselineStddev)
+}
+
+// CheckBaseline 检查主机各项指标的当前值是否偏离基线超过sigma倍标准差，上升和下降都会标记为异常。
+// 采样点不足的指标不做检查；与静态阈值一样，基线异常会将主机标记为异常。
+func CheckBaseline(node *NodeMetric, sigma float64) {
+	for metric, baseline := range node.Baselines {
+		if baseline.Samples < MinBaselineSamples {
+			continue
+		}
+
+		baseline.Deviation = (node.current(metric) - baseline.Mean) / baseline.stddev()
+		baseline.Deviation = math.Round(baseline.Deviation*100) / 100
+		if math.Abs(baseline.Deviation) > sigma {
+			baseline.Status = 1
+			node.Status = 1
+		}
+		node.Baselines[metric] = baseline
+	}
+}
+
+// current 返回主机指标的当前值
+func (n NodeMetric) current(metric string) float64 {
+	switch metric {
+	case MetricDisk:
+		return n.DiskNow
+	case MetricInode:
+		return n.InodeNow
+	case MetricCPU:
+		return n.CPUNow
+	case MetricMemory:
+		return n.MemNow
+	}
+	return 0
+}
+
+// baselineAbnormal 主机指标是否偏离基线
+func (n NodeMetric) baselineAbnormal(metric string) bool {
+	return n.Baselines[metric].Status == 1
+}
+
+// BaselineOf 返回主机指标的基线，没有足够的历史数据时第二个返回值为false
+func (n NodeMetric) BaselineOf(metric string) (Baseline, bool) {
+	baseline, ok := n.Baselines[metric]
+	return baseline, ok && baseline.Samples >= MinBaselineSamples
+}
+
+// BaselineRow HTML报告基线表格中的一行
+type BaselineRow struct {
+	Node    string
+	Metric  string
+	Current float64
+	Baseline
+}
+
+// BaselineRows 返回主机按指标顺序排列的基线，只包含有足够历史数据的指标
+func (n NodeMetric) BaselineRows() []BaselineRow {
+	var rows []BaselineRow
+	for _, metric := range TrendMetrics {
+		baseline, ok := n.BaselineOf(metric)
+		if !ok {
+			continue
+		}
+		rows = append(rows, BaselineRow{
+			Node:     n.Name,
+			Metric:   metric,
+			Current:  n.current(metric),
+			Baseline: baseline,
+		})
+	}
+	return rows
+}
+
+// BaselineRows 返回报告中按主机和指标顺序排列的基线
+func (d *ReportData) BaselineRows() []BaselineRow {
+	var rows []BaselineRow
+	for _, node := range d.Inspection.Node {
+		rows = append(rows, node.BaselineRows()...)
+	}
+	return rows
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// repeat 返回按values循环生成的n个采样点
+func repeat(n int, values ...float64) []float64 {
+	result := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, values[i%len(values)])
+	}
+	return result
+}
+
+var _ = Describe("Baselines", func() {
+	It("should compute the mean and standard deviation", func() {
+		baseline := NewBaseline([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+		Expect(baseline.Mean).To(Equal(5.0))
+		Expect(baseline.Stddev).To(Equal(2.0))
+		Expect(baseline.Samples).To(Equal(8))
+		Expect(baseline.Lower(2)).To(Equal(1.0))
+		Expect(baseline.Upper(3)).To(Equal(11.0))
+	})
+
+	It("should flag quiet hosts that change unusually", func() {
+		node := NodeMetric{
+			CPUNow: 30, MemNow: 70,
+			Baselines: map[string]Baseline{
+				MetricCPU:    NewBaseline(repeat(24, 4, 6)),
+				MetricMemory: NewBaseline(repeat(24, 68, 72)),
+			},
+		}
+		CheckThresholds(&node)
+		CheckBaseline(&node, 3)
+
+		Expect(node.CPUNowStatus).To(Equal(0))
+		Expect(node.Baselines[MetricCPU].Status).To(Equal(1))
+		Expect(node.Baselines[MetricCPU].Deviation).To(Equal(25.0))
+		Expect(node.Baselines[MetricMemory].Status).To(Equal(0))
+		Expect(node.Status).To(Equal(1))
+		Expect(node.AbnormalItems()).To(Equal([]string{MetricCPU}))
+	})
+
+	It("should flag drops and use the minimum standard deviation", func() {
+		node := NodeMetric{
+			MemNow:    46,
+			Baselines: map[string]Baseline{MetricMemory: NewBaseline(repeat(24, 50))},
+		}
+		CheckBaseline(&node, 3)
+		Expect(node.Baselines[MetricMemory].Deviation).To(Equal(-4.0))
+		Expect(node.Baselines[MetricMemory].Status).To(Equal(1))
+	})
+
+	It("should skip metrics without enough history", func() {
+		node := NodeMetric{
+			CPUNow:    90,
+			Baselines: map[string]Baseline{MetricCPU: NewBaseline(repeat(MinBaselineSamples-1, 5))},
+		}
+		CheckBaseline(&node, 3)
+		Expect(node.Status).To(Equal(0))
+		Expect(node.BaselineRows()).To(BeEmpty())
+	})
+
+	It("should render the baseline next to the static evaluation", func() {
+		data := sampleReportData()
+		data.Inspection.Baseline = &BaselineConfig{Days: 7, Sigma: 3, Step: time.Hour}
+		node := &data.Inspection.Node[0]
+		node.Baselines = map[string]Baseline{MetricCPU: NewBaseline(repeat(24, node.CPUNow-10))}
+		CheckBaseline(node, 3)
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("动态基线"))
+		Expect(html).To(ContainSubstring("按最近7天的历史数据"))
+		Expect(html).To(ContainSubstring("&#43;10σ"))
+
+		text, err := NewGenerator().generateText(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(text)).To(ContainSubstring("偏离基线: CPU使用率"))
+
+		raw, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var report JSONReport
+		Expect(json.Unmarshal(raw, &report)).To(Succeed())
+		Expect(report.Metadata.Baseline).To(Equal(&JSONBaselineConfig{Days: 7, Sigma: 3, StepSeconds: 3600}))
+		Expect(report.Nodes[0].Baseline).To(HaveKey(MetricCPU))
+		Expect(report.Nodes[0].Baseline[MetricCPU].Status).To(Equal("abnormal"))
+		Expect(report.Nodes[1].Baseline).To(BeNil())
+	})
+
+	It("should explain when there is not enough history", func() {
+		data := sampleReportData()
+		data.Inspection.Baseline = &BaselineConfig{Days: 7, Sigma: 3, Step: time.Hour}
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("历史数据不足"))
+	})
+})
@@ -0,0 +1,294 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point 趋势中的一个采样点，Value为百分比
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// 趋势图尺寸
+const (
+	sparklineWidth  = 120
+	sparklineHeight = 24
+	// heatmapBuckets 热力图的时间分段数
+	heatmapBuckets = 24
+)
+
+// TrendMetrics 绘制趋势图的指标，顺序与报告表格一致
+var TrendMetrics = []string{MetricDisk, MetricInode, MetricCPU, MetricMemory}
+
+// 趋势图配色
+var (
+	sparklineColor  = color.NRGBA{R: 0x0d, G: 0x6e, B: 0xfd, A: 0xff}
+	sparklineDanger = color.NRGBA{R: 0xdc, G: 0x35, B: 0x45, A: 0xff}
+	thresholdColor  = color.NRGBA{R: 0xad, G: 0xb5, B: 0xbd, A: 0xff}
+)
+
+// heatmapColors 热力图颜色等级，依次对应阈值的50%、80%、100%以下和超过阈值
+var heatmapColors = []string{"#e9f7ef", "#c3e6cb", "#ffeeba", "#dc3545"}
+
+// heatmapEmptyColor 没有数据的时段
+const heatmapEmptyColor = "#f1f3f5"
+
+// metricThreshold 返回指标的默认使用率阈值，主机生效的阈值见Thresholds.usage
+func metricThreshold(metric string) float64 {
+	switch metric {
+	case MetricDisk:
+		return DiskThreshold
+	case MetricInode:
+		return InodeThreshold
+	case MetricCPU:
+		return CPUThreshold
+	case MetricMemory:
+		return MemThreshold
+	}
+	return 100
+}
+
+// Trend 返回主机某项指标的趋势
+func (n NodeMetric) Trend(metric string) []Point {
+	return n.Trends[metric]
+}
+
+// HasTrends 报告中是否包含趋势数据
+func (d *ReportData) HasTrends() bool {
+	for _, node := range d.Inspection.Node {
+		for _, points := range node.Trends {
+			if len(points) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HeatmapCell 热力图单元格，Valid为false表示该时段没有数据
+type HeatmapCell struct {
+	Start time.Time
+	Value float64
+	Valid bool
+}
+
+// HeatmapRow 热力图中一台主机的一行
+type HeatmapRow struct {
+	Name  string
+	Cells []HeatmapCell
+	// 主机生效的使用率阈值，单元格按与该阈值的接近程度着色
+	Threshold float64
+}
+
+// Heatmap 全部主机某项指标的热力图，每格取该时段内的最大值
+type Heatmap struct {
+	Metric string
+	// 默认的使用率阈值，各主机生效的阈值见HeatmapRow.Threshold
+	Threshold float64
+	Start     time.Time
+	End       time.Time
+	Rows      []HeatmapRow
+}
+
+// Heatmaps 返回所有有趋势数据的指标的热力图
+func (d *ReportData) Heatmaps() []*Heatmap {
+	var heatmaps []*Heatmap
+	for _, metric := range TrendMetrics {
+		if heatmap := d.Heatmap(metric); heatmap != nil {
+			heatmaps = append(heatmaps, heatmap)
+		}
+	}
+	return heatmaps
+}
+
+// Heatmap 返回某项指标的热力图，没有数据时返回nil
+func (d *ReportData) Heatmap(metric string) *Heatmap {
+	var start, end time.Time
+	for _, node := range d.Inspection.Node {
+		for _, p := range node.Trend(metric) {
+			if start.IsZero() || p.Time.Before(start) {
+				start = p.Time
+			}
+			if p.Time.After(end) {
+				end = p.Time
+			}
+		}
+	}
+	if start.IsZero() {
+		return nil
+	}
+
+	width := end.Sub(start) / heatmapBuckets
+	heatmap := &Heatmap{
+		Metric:    metric,
+		Threshold: d.Inspection.Thresholds.usage(metric),
+		Start:     start,
+		End:       end,
+		Rows:      make([]HeatmapRow, 0, len(d.Inspection.Node)),
+	}
+	for _, node := range d.Inspection.Node {
+		cells := make([]HeatmapCell, heatmapBuckets)
+		for i := range cells {
+			cells[i].Start = start.Add(time.Duration(i) * width)
+		}
+		for _, p := range node.Trend(metric) {
+			index := 0
+			if width > 0 {
+				index = int(p.Time.Sub(start) / width)
+			}
+			if index >= heatmapBuckets {
+				index = heatmapBuckets - 1
+			}
+			if !cells[index].Valid || p.Value > cells[index].Value {
+				cells[index].Value = p.Value
+				cells[index].Valid = true
+			}
+		}
+		heatmap.Rows = append(heatmap.Rows, HeatmapRow{Name: node.Name, Cells: cells, Threshold: node.Thresholds.usage(metric)})
+	}
+	return heatmap
+}
+
+// heatColor 按与阈值的接近程度返回热力图单元格的背景色
+func heatColor(cell HeatmapCell, threshold float64) string {
+	if !cell.Valid {
+		return heatmapEmptyColor
+	}
+	switch ratio := cell.Value / threshold; {
+	case ratio < 0.5:
+		return heatmapColors[0]
+	case ratio < 0.8:
+		return heatmapColors[1]
+	case ratio <= 1:
+		return heatmapColors[2]
+	}
+	return heatmapColors[3]
+}
+
+// sparklinePoints 将采样点换算为图中的坐标，纵轴固定为0-100%
+func sparklinePoints(points []Point) [][2]float64 {
+	coords := make([][2]float64, 0, len(points))
+	if len(points) == 0 {
+		return coords
+	}
+
+	first, last := points[0].Time, points[len(points)-1].Time
+	span := last.Sub(first)
+	for _, p := range points {
+		x := 0.0
+		if span > 0 {
+			x = float64(p.Time.Sub(first)) / float64(span) * (sparklineWidth - 1)
+		}
+		coords = append(coords, [2]float64{x, sparklineY(p.Value)})
+	}
+	return coords
+}
+
+// sparklineY 将百分比换算为纵坐标
+func sparklineY(value float64) float64 {
+	value = math.Max(0, math.Min(100, value))
+	return (sparklineHeight - 1) - value/100*(sparklineHeight-2)
+}
+
+// sparklineSVG 生成内联SVG趋势图，虚线为阈值，最新值超过阈值时折线标红
+func sparklineSVG(points []Point, threshold float64, label string) string {
+	stroke := "#0d6efd"
+	if points[len(points)-1].Value > threshold {
+		stroke = "#dc3545"
+	}
+
+	coords := make([]string, 0, len(points))
+	for _, c := range sparklinePoints(points) {
+		coords = append(coords, formatCoord(c[0])+","+formatCoord(c[1]))
+	}
+	thresholdY := formatCoord(sparklineY(threshold))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, `<svg width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		sparklineWidth, sparklineHeight, sparklineWidth, sparklineHeight, template.HTMLEscapeString(label))
+	fmt.Fprintf(&buf, `<line x1="0" y1="%s" x2="%d" y2="%s" stroke="#adb5bd" stroke-width="1" stroke-dasharray="2,2"/>`,
+		thresholdY, sparklineWidth, thresholdY)
+	fmt.Fprintf(&buf, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`,
+		stroke, strings.Join(coords, " "))
+	buf.WriteString(`</svg>`)
+	return buf.String()
+}
+
+// formatCoord 格式化坐标，保留一位小数
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+// sparklinePNG 生成与SVG内容一致的PNG图片，供不支持SVG的Outlook显示
+func sparklinePNG(points []Point, threshold float64) ([]byte, error) {
+	img := image.NewNRGBA(image.Rect(0, 0, sparklineWidth, sparklineHeight))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	thresholdY := int(math.Round(sparklineY(threshold)))
+	for x := 0; x < sparklineWidth; x += 4 {
+		img.Set(x, thresholdY, thresholdColor)
+		img.Set(x+1, thresholdY, thresholdColor)
+	}
+
+	stroke := sparklineColor
+	if points[len(points)-1].Value > threshold {
+		stroke = sparklineDanger
+	}
+	coords := sparklinePoints(points)
+	if len(coords) == 1 {
+		drawLine(img, coords[0], coords[0], stroke)
+	}
+	for i := 1; i < len(coords); i++ {
+		drawLine(img, coords[i-1], coords[i], stroke)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("生成趋势图PNG失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine 在两点之间画线，线宽为2像素
+func drawLine(img *image.NRGBA, from, to [2]float64, c color.NRGBA) {
+	steps := int(math.Max(math.Abs(to[0]-from[0]), math.Abs(to[1]-from[1]))) + 1
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(from[0] + (to[0]-from[0])*t))
+		y := int(math.Round(from[1] + (to[1]-from[1])*t))
+		img.Set(x, y, c)
+		img.Set(x, y-1, c)
+	}
+}
+
+// sparkline 生成嵌入报告的趋势图，threshold为主机生效的使用率阈值。
+// 大多数客户端显示内联SVG；Outlook不支持SVG，通过条件注释改为显示PNG data URI。
+func sparkline(points []Point, threshold float64, label string) (template.HTML, error) {
+	if len(points) == 0 {
+		return "", nil
+	}
+
+	pngData, err := sparklinePNG(points, threshold)
+	if err != nil {
+		return "", err
+	}
+
+	html := `<!--[if mso]><img src="data:image/png;base64,` + base64.StdEncoding.EncodeToString(pngData) +
+		`" width="` + strconv.Itoa(sparklineWidth) + `" height="` + strconv.Itoa(sparklineHeight) +
+		`" alt="` + template.HTMLEscapeString(label) + `" style="display: block; border: 0;" /><![endif]-->` +
+		`<!--[if !mso]><!-->` + sparklineSVG(points, threshold, label) + `<!--<![endif]-->`
+	return template.HTML(html), nil
+}
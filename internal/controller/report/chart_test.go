@@ -0,0 +1,139 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"regexp"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// sampleTrend 生成从start开始每15分钟一个点的趋势
+func sampleTrend(start time.Time, values ...float64) []Point {
+	points := make([]Point, 0, len(values))
+	for i, v := range values {
+		points = append(points, Point{Time: start.Add(time.Duration(i) * 15 * time.Minute), Value: v})
+	}
+	return points
+}
+
+// sampleTrendData 返回带趋势数据的报告
+func sampleTrendData() *ReportData {
+	data := sampleReportData()
+	start := data.Metadata.GeneratedAt.Add(-24 * time.Hour)
+	for i := range data.Inspection.Node {
+		node := &data.Inspection.Node[i]
+		node.Trends = map[string][]Point{
+			MetricCPU:    sampleTrend(start, 10, 20, node.CPUNow),
+			MetricMemory: sampleTrend(start, 50, 55, node.MemNow),
+		}
+	}
+	return data
+}
+
+var _ = Describe("Charts", func() {
+	It("should embed SVG sparklines with a PNG fallback for Outlook", func() {
+		html, err := NewGenerator().GenerateHTML(sampleTrendData())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(html).To(ContainSubstring(`<svg width="120" height="24"`))
+		Expect(html).To(ContainSubstring(`<!--[if mso]><img src="data:image/png;base64,`))
+		Expect(html).To(ContainSubstring(`<!--[if !mso]><!--><svg`))
+		Expect(html).NotTo(ContainSubstring("<script"))
+		Expect(html).NotTo(ContainSubstring("http://"))
+		Expect(html).NotTo(ContainSubstring("https://"))
+
+		// 只有CPU和内存有趋势数据
+		Expect(html).To(ContainSubstring("CPU使用率热力图"))
+		Expect(html).To(ContainSubstring("内存使用率热力图"))
+		Expect(html).NotTo(ContainSubstring("硬盘使用率（各分区最大值）热力图"))
+	})
+
+	It("should label the disk and inode trends as the maximum across filesystems", func() {
+		start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		data := sampleReportData()
+		data.Inspection.Node[0].Trends = map[string][]Point{MetricDisk: sampleTrend(start, 60, 70)}
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("硬盘使用率（各分区最大值）热力图"))
+		Expect(html).To(ContainSubstring(`aria-label="硬盘使用率（各分区最大值）"`))
+		Expect(html).To(ContainSubstring("inode使用率（各分区最大值）</th>"))
+	})
+
+	It("should not render the trend section without trend data", func() {
+		html, err := NewGenerator().GenerateHTML(sampleReportData())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).NotTo(ContainSubstring("<svg"))
+		Expect(html).NotTo(ContainSubstring("24小时趋势"))
+	})
+
+	It("should draw the line in red when the latest value exceeds the threshold", func() {
+		start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		Expect(sparklineSVG(sampleTrend(start, 10, 20), CPUThreshold, "cpu")).To(ContainSubstring(`stroke="#0d6efd"`))
+		Expect(sparklineSVG(sampleTrend(start, 10, 90), CPUThreshold, "cpu")).To(ContainSubstring(`stroke="#dc3545"`))
+	})
+
+	It("should encode a PNG of the sparkline size", func() {
+		start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		html, err := sparkline(sampleTrend(start, 10, 50, 100), CPUThreshold, "CPU")
+		Expect(err).NotTo(HaveOccurred())
+
+		match := regexp.MustCompile(`data:image/png;base64,([A-Za-z0-9+/=]+)`).FindStringSubmatch(string(html))
+		Expect(match).To(HaveLen(2))
+		data, err := base64.StdEncoding.DecodeString(match[1])
+		Expect(err).NotTo(HaveOccurred())
+		img, err := png.Decode(bytes.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(img.Bounds().Dx()).To(Equal(sparklineWidth))
+		Expect(img.Bounds().Dy()).To(Equal(sparklineHeight))
+	})
+
+	It("should bucket the heatmap by the maximum value", func() {
+		start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		data := &ReportData{Inspection: InspectionData{Node: []NodeMetric{
+			{Name: "a", Trends: map[string][]Point{MetricCPU: {
+				{Time: start, Value: 10},
+				{Time: start.Add(10 * time.Minute), Value: 70},
+				{Time: start.Add(24 * time.Hour), Value: 30},
+			}}},
+			{Name: "b"},
+		}}}
+
+		heatmap := data.Heatmap(MetricCPU)
+		Expect(heatmap).NotTo(BeNil())
+		Expect(heatmap.Rows).To(HaveLen(2))
+		Expect(heatmap.Rows[0].Cells).To(HaveLen(heatmapBuckets))
+		Expect(heatmap.Rows[0].Cells[0]).To(HaveField("Value", 70.0))
+		Expect(heatmap.Rows[0].Cells[heatmapBuckets-1]).To(HaveField("Value", 30.0))
+		Expect(heatmap.Rows[0].Cells[1].Valid).To(BeFalse())
+		Expect(heatmap.Rows[1].Cells[0].Valid).To(BeFalse())
+
+		Expect(heatColor(heatmap.Rows[0].Cells[0], CPUThreshold)).To(Equal("#dc3545"))
+		Expect(heatColor(heatmap.Rows[0].Cells[1], CPUThreshold)).To(Equal(heatmapEmptyColor))
+		Expect(data.Heatmap(MetricDisk)).To(BeNil())
+	})
+
+	It("should use the thresholds resolved for each node", func() {
+		start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		trend := map[string][]Point{MetricCPU: sampleTrend(start, 70, 70)}
+		data := sampleReportData()
+		data.Inspection.Node[0].Trends = trend
+		data.Inspection.Node[1].Trends = trend
+		data.Inspection.Node[1].Thresholds = Thresholds{CPU: 90}
+
+		heatmap := data.Heatmap(MetricCPU)
+		Expect(heatmap.Rows[0].Threshold).To(Equal(float64(CPUThreshold)))
+		Expect(heatmap.Rows[1].Threshold).To(Equal(90.0))
+		Expect(heatColor(heatmap.Rows[1].Cells[0], heatmap.Rows[1].Threshold)).To(Equal(heatmapColors[1]))
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(html, `stroke="#dc3545"`)).To(Equal(1))
+		Expect(strings.Count(html, `stroke="#0d6efd"`)).To(Equal(1))
+	})
+})
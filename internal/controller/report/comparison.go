@@ -0,0 +1,187 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DefaultComparisonOffset 未配置对比偏移时与24小时前对比
+const DefaultComparisonOffset = "24h"
+
+// Comparison 对比偏移配置
+type Comparison struct {
+	// 相对巡检时间的偏移，Prometheus时长格式，如24h、7d
+	Offset string
+	// 偏移对应的时长
+	Duration time.Duration
+	// 差值阈值（百分点），超过时标记为异常
+	RateThreshold float64
+}
+
+// DefaultComparison 默认的对比偏移
+var DefaultComparison = Comparison{
+	Offset:        DefaultComparisonOffset,
+	Duration:      24 * time.Hour,
+	RateThreshold: RateThreshold,
+}
+
+// OffsetMetric 主机与某个额外对比偏移相比的各项指标，数值均为百分比
+type OffsetMetric struct {
+	Offset string
+	// 该偏移的对比值查询失败（如超出Prometheus数据保留时长）时为false
+	Valid bool
+	// 硬盘使用率，对比值取使用率最大的分区，差值状态按分区检查
+	DiskOffset     float64
+	DiskRate       float64
+	DiskRateStatus int
+	// Inode使用率
+	InodeOffset     float64
+	InodeRate       float64
+	InodeRateStatus int
+	// CPU使用率
+	CPUOffset     float64
+	CPURate       float64
+	CPURateStatus int
+	// 内存使用率
+	MemOffset     float64
+	MemRate       float64
+	MemRateStatus int
+	// 各分区的对比结果，与主对比一致按分区检查硬盘和inode的差值
+	Filesystems []FilesystemOffset
+}
+
+// FilesystemOffset 单个分区与额外对比偏移相比的硬盘和inode使用率
+type FilesystemOffset struct {
+	Device     string
+	Mountpoint string
+	// 硬盘使用率
+	DiskOffset     float64
+	DiskRate       float64
+	DiskRateStatus int
+	// Inode使用率
+	InodeOffset     float64
+	InodeRate       float64
+	InodeRateStatus int
+}
+
+// durationPattern Prometheus时长格式，与CRD中的校验规则一致
+var durationPattern = regexp.MustCompile(`^([0-9]+)(ms|s|m|h|d|w|y)$`)
+
+// durationUnits Prometheus时长单位
+var durationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+// ParseOffset 解析Prometheus时长格式的对比偏移，如7d
+func ParseOffset(offset string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(offset)
+	if match == nil {
+		return 0, fmt.Errorf("对比偏移%q格式无效", offset)
+	}
+	n, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("对比偏移%q无效", offset)
+	}
+	return time.Duration(n) * durationUnits[match[2]], nil
+}
+
+// comparisons 返回报告中的对比偏移，第一个为主对比偏移
+func (d *ReportData) comparisons() []Comparison {
+	if len(d.Inspection.Comparisons) == 0 {
+		return []Comparison{DefaultComparison}
+	}
+	return d.Inspection.Comparisons
+}
+
+// ComparisonOffset 返回主对比偏移，主机的Offset和Rate字段按该偏移计算
+func (d *ReportData) ComparisonOffset() string {
+	return d.comparisons()[0].Offset
+}
+
+// RateThreshold 返回主对比偏移的差值阈值
+func (d *ReportData) RateThreshold() float64 {
+	return d.comparisons()[0].RateThreshold
+}
+
+// ExtraComparisons 返回主对比偏移之外的对比偏移，与主机的Offsets一一对应
+func (d *ReportData) ExtraComparisons() []Comparison {
+	return d.comparisons()[1:]
+}
+
+// MetricColumns 返回HTML结果表格中主机名称和状态之间的列数
+func (d *ReportData) MetricColumns() int {
+	columns := 12 + 4*len(d.ExtraComparisons())
+	if d.Inspection.Forecast != nil {
+		columns++
+	}
+	return columns
+}
+
+// TableColumns 返回HTML结果表格的总列数
+func (d *ReportData) TableColumns() int {
+	return d.MetricColumns() + 2
+}
+
+// checkOffsets 按各额外对比偏移的差值阈值检查主机的差值
+func checkOffsets(node *NodeMetric, comparisons []Comparison) {
+	for i := range node.Offsets {
+		offset := &node.Offsets[i]
+		if !offset.Valid || i >= len(comparisons) {
+			continue
+		}
+
+		threshold := comparisons[i].RateThreshold
+		if len(offset.Filesystems) > 0 {
+			checkFilesystemOffsets(node, offset, threshold)
+		} else {
+			if node.deltaExceeded(MetricDisk, offset.DiskOffset, offset.DiskRate, threshold) {
+				offset.DiskRateStatus = 1
+			}
+			if node.deltaExceeded(MetricInode, offset.InodeOffset, offset.InodeRate, threshold) {
+				offset.InodeRateStatus = 1
+			}
+		}
+		if node.deltaExceeded(MetricCPU, offset.CPUOffset, offset.CPURate, threshold) {
+			offset.CPURateStatus = 1
+		}
+		if node.deltaExceeded(MetricMemory, offset.MemOffset, offset.MemRate, threshold) {
+			offset.MemRateStatus = 1
+		}
+		if offset.DiskRateStatus == 1 || offset.InodeRateStatus == 1 || offset.CPURateStatus == 1 || offset.MemRateStatus == 1 {
+			node.Status = 1
+		}
+	}
+}
+
+// checkFilesystemOffsets 按分区检查与额外对比偏移相比的硬盘和inode差值，任一分区超过阈值时主机对应指标异常
+func checkFilesystemOffsets(node *NodeMetric, offset *OffsetMetric, threshold float64) {
+	for i := range offset.Filesystems {
+		fs := &offset.Filesystems[i]
+		if node.deltaExceeded(MetricDisk, fs.DiskOffset, fs.DiskRate, threshold) {
+			fs.DiskRateStatus = 1
+			offset.DiskRateStatus = 1
+		}
+		if node.deltaExceeded(MetricInode, fs.InodeOffset, fs.InodeRate, threshold) {
+			fs.InodeRateStatus = 1
+			offset.InodeRateStatus = 1
+		}
+	}
+}
+
+// offsetAbnormal 主机与任一额外对比偏移相比，指标差值是否超过阈值
+func (n NodeMetric) offsetAbnormal(status func(OffsetMetric) int) bool {
+	for _, offset := range n.Offsets {
+		if status(offset) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// sampleComparisonData 返回配置了7d和30d额外对比偏移的报告
+func sampleComparisonData() *ReportData {
+	data := sampleReportData()
+	data.Inspection.Comparisons = []Comparison{
+		DefaultComparison,
+		{Offset: "7d", Duration: 7 * 24 * time.Hour, RateThreshold: 15},
+		{Offset: "30d", Duration: 30 * 24 * time.Hour, RateThreshold: 20},
+	}
+	for i := range data.Inspection.Node {
+		node := &data.Inspection.Node[i]
+		node.Status = 0
+		node.Offsets = []OffsetMetric{
+			{Offset: "7d", Valid: true,
+				DiskOffset: node.DiskNow - 16, DiskRate: 16,
+				InodeOffset: node.InodeNow, CPUOffset: node.CPUNow, MemOffset: node.MemNow},
+			{Offset: "30d"},
+		}
+		CheckThresholds(node, data.Inspection.Comparisons...)
+	}
+	return data
+}
+
+var _ = Describe("Comparisons", func() {
+	It("should parse Prometheus durations", func() {
+		for offset, expected := range map[string]time.Duration{
+			"90m": 90 * time.Minute,
+			"1d":  24 * time.Hour,
+			"7d":  7 * 24 * time.Hour,
+			"2w":  14 * 24 * time.Hour,
+		} {
+			Expect(ParseOffset(offset)).To(Equal(expected), offset)
+		}
+		for _, offset := range []string{"", "0d", "7", "1.5d", "-1d", "7days"} {
+			_, err := ParseOffset(offset)
+			Expect(err).To(HaveOccurred(), offset)
+		}
+	})
+
+	It("should check each offset against its own delta threshold", func() {
+		node := NodeMetric{
+			Name:    "a",
+			DiskNow: 50, DiskOffset: 45, DiskRate: 5,
+			Offsets: []OffsetMetric{
+				{Offset: "7d", Valid: true, DiskOffset: 38, DiskRate: 12, CPURate: 16},
+			},
+		}
+		comparisons := []Comparison{
+			{Offset: "1d", RateThreshold: 4},
+			{Offset: "7d", RateThreshold: 15},
+		}
+		CheckThresholds(&node, comparisons...)
+
+		Expect(node.DiskRateStatus).To(Equal(1))
+		Expect(node.Offsets[0].DiskRateStatus).To(Equal(0))
+		Expect(node.Offsets[0].CPURateStatus).To(Equal(1))
+		Expect(node.Status).To(Equal(1))
+		Expect(node.AbnormalItems()).To(Equal([]string{MetricDisk, MetricCPU}))
+	})
+
+	It("should ignore offsets that could not be queried", func() {
+		node := NodeMetric{Offsets: []OffsetMetric{{Offset: "30d", DiskRate: 50}}}
+		CheckThresholds(&node, DefaultComparison, Comparison{Offset: "30d", RateThreshold: 10})
+		Expect(node.Status).To(Equal(0))
+	})
+
+	It("should render a column group per extra offset", func() {
+		data := sampleComparisonData()
+		Expect(data.ComparisonOffset()).To(Equal("24h"))
+		Expect(data.MetricColumns()).To(Equal(20))
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("硬盘前7d"))
+		Expect(html).To(ContainSubstring("内存前30d"))
+		Expect(html).To(ContainSubstring("另外与 7d、30d 前的值对比"))
+		Expect(html).To(ContainSubstring("29.5% (&#43;16)"))
+
+		text, err := NewGenerator().generateText(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(text)).To(ContainSubstring("与7d前对比: 硬盘 29.5% (+16)"))
+		Expect(string(text)).NotTo(ContainSubstring("与30d前对比"))
+	})
+
+	It("should label the primary columns with the primary offset", func() {
+		data := sampleReportData()
+		data.Inspection.Comparisons = []Comparison{{Offset: "7d", Duration: 7 * 24 * time.Hour, RateThreshold: 10}}
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("硬盘使用率前7d"))
+		Expect(html).NotTo(ContainSubstring("前24h"))
+
+		csv, err := GenerateCSV(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(csv)).To(ContainSubstring("硬盘使用率前7d(%)"))
+	})
+
+	It("should export extra offsets in the JSON report", func() {
+		raw, err := GenerateJSON(sampleComparisonData())
+		Expect(err).NotTo(HaveOccurred())
+
+		var report JSONReport
+		Expect(json.Unmarshal(raw, &report)).To(Succeed())
+		Expect(report.Metadata.ComparisonOffset).To(Equal("24h"))
+		Expect(report.Metadata.Comparisons).To(Equal([]JSONComparison{
+			{Offset: "7d", RateThreshold: 15},
+			{Offset: "30d", RateThreshold: 20},
+		}))
+
+		offsets := report.Nodes[0].Offsets
+		Expect(offsets).To(HaveLen(2))
+		Expect(offsets[0].Disk).To(Equal(&JSONOffsetValue{Previous: 29.5, Delta: 16, DeltaStatus: "abnormal"}))
+		Expect(offsets[1].Valid).To(BeFalse())
+		Expect(offsets[1].Disk).To(BeNil())
+		Expect(report.Nodes[0].Status).To(Equal("abnormal"))
+	})
+})
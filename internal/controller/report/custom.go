@@ -0,0 +1,172 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sync"
+	"time"
+)
+
+// ParseTemplate 解析并校验自定义报告模板。
+// 除语法检查外，还会使用示例数据试执行一次，提前发现引用了不存在字段等运行期错误。
+func ParseTemplate(name, text string) (*template.Template, error) {
+	data := validationData()
+	tmpl, err := template.New(name).Funcs(NewGenerator().funcMap(data.Catalog())).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %w", err)
+	}
+
+	// 在副本上试执行，避免html/template执行后无法再替换模板函数
+	validation, err := tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("复制模板失败: %w", err)
+	}
+	if err := validation.Execute(io.Discard, data); err != nil {
+		return nil, fmt.Errorf("校验模板失败: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// validationData 返回用于校验模板的示例数据，填充报告的全部可选部分，
+// 使只在开启分区明细、额外对比偏移、趋势图、基线、静默规则等功能时才会执行的模板分支同样得到校验
+func validationData() *ReportData {
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	comparisons := []Comparison{DefaultComparison, {Offset: "7d", Duration: 7 * 24 * time.Hour, RateThreshold: 15}}
+	deltaRules := []DeltaRule{{Metric: MetricCPU, Mode: DeltaRelative, Direction: DirectionBoth, Threshold: 50, MinBaseline: 5}}
+
+	trend := func(values ...float64) []Point {
+		points := make([]Point, 0, len(values))
+		for i, v := range values {
+			points = append(points, Point{Time: now.Add(time.Duration(i-len(values)+1) * time.Hour), Value: v})
+		}
+		return points
+	}
+
+	node := NodeMetric{
+		Name:    "127.0.0.1:9100",
+		Health:  NodeHealth{State: NodeStateOK, ScrapeAge: 15 * time.Second},
+		DiskNow: 85, DiskOffset: 70, DiskRate: 15,
+		InodeNow: 10, InodeOffset: 10,
+		CPUNow: 20, CPUOffset: 18, CPURate: 2,
+		MemNow: 50, MemOffset: 48, MemRate: 2,
+		Offsets: []OffsetMetric{{
+			Offset: "7d", Valid: true,
+			DiskOffset: 60, DiskRate: 25, InodeOffset: 10, CPUOffset: 20, MemOffset: 45, MemRate: 5,
+		}},
+		Filesystems: []FilesystemMetric{
+			{Device: "/dev/sda1", Mountpoint: "/", FSType: "ext4",
+				DiskNow: 85, DiskOffset: 70, DiskRate: 15, InodeNow: 10, InodeOffset: 10},
+			{Device: "/dev/sdb1", Mountpoint: "/data", FSType: "xfs", DiskThreshold: 90,
+				DiskNow: 40, DiskOffset: 40, InodeNow: 5, InodeOffset: 5},
+		},
+		DiskForecast: &DiskForecast{Mountpoint: "/", Days: 3},
+		Labels:       map[string]string{"job": "node"},
+		DataSource:   "validation",
+		Thresholds: Thresholds{
+			Disk: 80, Inode: 60, CPU: 60, Memory: 80,
+			Deltas:  NewThresholds(deltaRules).Deltas,
+			Sources: []string{"validation"},
+		},
+		Baselines: map[string]Baseline{MetricCPU: {Mean: 10, Stddev: 2, Samples: MinBaselineSamples}},
+		Trends: map[string][]Point{
+			MetricDisk:   trend(80, 85),
+			MetricInode:  trend(10, 10),
+			MetricCPU:    trend(15, 20),
+			MetricMemory: trend(45, 50),
+		},
+	}
+	CheckThresholds(&node, comparisons...)
+	CheckForecast(&node, 7)
+	CheckBaseline(&node, 3)
+
+	// 异常全部被静默规则确认的主机
+	acknowledged := NodeMetric{Name: "127.0.0.2:9100", CPUNow: 90, CPUOffset: 90}
+	CheckThresholds(&acknowledged, comparisons...)
+	unreachable := NewUnreachableNode("127.0.0.3:9100", NodeHealth{State: NodeStateDown, ScrapeAge: 10 * time.Minute, Reason: "up=0"})
+
+	changes := &ChangeSet{
+		Previous: now.Add(-24 * time.Hour),
+		New:      []Change{{Node: node.Name, Metric: MetricDisk, Kind: ChangeNew, Since: now}},
+		Ongoing:  []Change{{Node: unreachable.Name, Metric: MetricReachability, Kind: ChangeOngoing, Since: now.Add(-48 * time.Hour), Duration: 48 * time.Hour}},
+		Resolved: []Change{{Node: acknowledged.Name, Metric: MetricMemory, Kind: ChangeResolved, Since: now.Add(-24 * time.Hour)}},
+	}
+	node.Changes = changes.New
+	unreachable.Changes = changes.Ongoing
+
+	data := newReportData("validation", []NodeMetric{node, acknowledged, unreachable}, "", now)
+	data.Inspection.Forecast = &ForecastConfig{Window: "24h", ThresholdDays: 7}
+	data.Inspection.Comparisons = comparisons
+	data.Inspection.Thresholds = NewThresholds(deltaRules)
+	data.Inspection.Baseline = &BaselineConfig{Days: 7, Sigma: 3, Step: time.Hour}
+	data.Changes = changes
+	data.QueryWarnings = []string{"validation"}
+	// 示例规则均有效，不会返回错误
+	_ = ApplySilences(data, []Silence{
+		{Node: node.Name, Metric: MetricDisk, Reason: "validation", Owner: "validation", ExpiresAt: now.Add(time.Hour)},
+		{Node: acknowledged.Name, Metric: MetricCPU, Reason: "validation", Owner: "validation", ExpiresAt: now.Add(time.Hour)},
+		{Node: node.Name, Metric: MetricMemory, Reason: "validation", Owner: "validation", ExpiresAt: now.Add(-time.Hour)},
+	}, now)
+	return data
+}
+
+// templateEntry 缓存的模板解析结果
+type templateEntry struct {
+	// 模板来源，如ConfigMap名称和键
+	source          string
+	resourceVersion string
+	tmpl            *template.Template
+	err             error
+}
+
+// TemplateCache 缓存已解析的自定义模板，每个key只保留最近一次解析的结果，
+// 模板来源和resourceVersion均未变化时不再重复解析，解析失败的结果同样会被缓存
+type TemplateCache struct {
+	mu      sync.Mutex
+	entries map[string]templateEntry
+}
+
+// NewTemplateCache 创建模板缓存
+func NewTemplateCache() *TemplateCache {
+	return &TemplateCache{
+		entries: make(map[string]templateEntry),
+	}
+}
+
+// Get 返回key对应的已解析模板，source或resourceVersion变化时重新解析并替换原有结果。
+// 缓存为nil时直接解析，不做缓存。
+func (c *TemplateCache) Get(key, source, resourceVersion, text string) (*template.Template, error) {
+	if c == nil {
+		return ParseTemplate(source, text)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok && entry.source == source && entry.resourceVersion == resourceVersion {
+		return entry.tmpl, entry.err
+	}
+
+	tmpl, err := ParseTemplate(source, text)
+	c.entries[key] = templateEntry{
+		source:          source,
+		resourceVersion: resourceVersion,
+		tmpl:            tmpl,
+		err:             err,
+	}
+
+	return tmpl, err
+}
+
+// Delete 删除key对应的缓存，在引用模板的对象被删除或不再引用模板时调用
+func (c *TemplateCache) Delete(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
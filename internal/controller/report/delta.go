@@ -0,0 +1,134 @@
+package report
+
+import (
+	"math"
+	"sort"
+)
+
+// 差值计算方式
+const (
+	// DeltaAbsolute 按百分点计算差值
+	DeltaAbsolute = "absolute"
+	// DeltaRelative 按相对对比值的变化百分比计算差值
+	DeltaRelative = "relative"
+)
+
+// 差值方向，对应消息目录中的delta.direction.<direction>
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+	DirectionBoth = "both"
+)
+
+// DeltaRule 单项指标的差值规则
+type DeltaRule struct {
+	Metric string
+	// 计算方式，见Delta*常量，为空时按百分点计算
+	Mode string
+	// 方向，见Direction*常量，为空时只检查上升
+	Direction string
+	// 差值阈值，按百分点计算时单位为百分点，按相对变化计算时单位为百分比；
+	// 为0时使用对比偏移的差值阈值
+	Threshold float64
+	// 对比值低于该值（百分比）时不检查差值，避免低使用率时的小幅波动被放大
+	MinBaseline float64
+}
+
+// Thresholds 主机生效的阈值规则，零值表示全部使用默认阈值
+type Thresholds struct {
+	// 使用率阈值（百分比），为0时使用DiskThreshold等默认阈值
+	Disk   float64
+	Inode  float64
+	CPU    float64
+	Memory float64
+	// 按指标配置的差值规则，key为指标名称
+	Deltas map[string]DeltaRule
+	// 生效的阈值覆盖规则，按具体程度从高到低排列，未使用覆盖规则时为空
+	Sources []string
+}
+
+// NewThresholds 按差值规则列表构造阈值规则，同一指标配置多次时使用最后一条
+func NewThresholds(rules []DeltaRule) Thresholds {
+	if len(rules) == 0 {
+		return Thresholds{}
+	}
+	deltas := make(map[string]DeltaRule, len(rules))
+	for _, rule := range rules {
+		deltas[rule.Metric] = rule
+	}
+	return Thresholds{Deltas: deltas}
+}
+
+// usage 返回指标生效的使用率阈值
+func (t Thresholds) usage(metric string) float64 {
+	var threshold float64
+	switch metric {
+	case MetricDisk:
+		threshold = t.Disk
+	case MetricInode:
+		threshold = t.Inode
+	case MetricCPU:
+		threshold = t.CPU
+	case MetricMemory:
+		threshold = t.Memory
+	}
+	if threshold > 0 {
+		return threshold
+	}
+	return metricThreshold(metric)
+}
+
+// delta 返回指标生效的差值规则，未配置时按百分点检查上升
+func (t Thresholds) delta(metric string) DeltaRule {
+	if rule, ok := t.Deltas[metric]; ok {
+		return rule
+	}
+	return DeltaRule{Metric: metric}
+}
+
+// DeltaRules 返回按报告中的指标顺序排列的差值规则
+func (t Thresholds) DeltaRules() []DeltaRule {
+	rules := make([]DeltaRule, 0, len(t.Deltas))
+	for _, rule := range t.Deltas {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return metricOrder[rules[i].Metric] < metricOrder[rules[j].Metric]
+	})
+	return rules
+}
+
+// Exceeded 检查差值是否超过阈值。
+// previous为对比值，delta为当前值与对比值之差（百分点），defaultThreshold为规则未配置阈值时使用的阈值。
+// 按相对变化计算时对比值为0无法计算变化比例，不做检查。
+func (r DeltaRule) Exceeded(previous, delta, defaultThreshold float64) bool {
+	if previous < r.MinBaseline {
+		return false
+	}
+
+	threshold := r.Threshold
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+
+	change := delta
+	if r.Mode == DeltaRelative {
+		if previous <= 0 {
+			return false
+		}
+		change = delta / previous * 100
+	}
+
+	switch r.Direction {
+	case DirectionDown:
+		return -change > threshold
+	case DirectionBoth:
+		return math.Abs(change) > threshold
+	}
+	return change > threshold
+}
+
+// deltaExceeded 按主机生效的差值规则检查指标的差值
+func (n *NodeMetric) deltaExceeded(metric string, previous, delta, defaultThreshold float64) bool {
+	return n.Thresholds.delta(metric).Exceeded(previous, delta, defaultThreshold)
+}
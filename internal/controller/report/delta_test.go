@@ -0,0 +1,81 @@
+package report
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Delta rules", func() {
+	DescribeTable("should evaluate the change against the rule",
+		func(rule DeltaRule, previous, delta float64, expected bool) {
+			Expect(rule.Exceeded(previous, delta, RateThreshold)).To(Equal(expected))
+		},
+		Entry("default rule flags increases", DeltaRule{}, 50.0, 11.0, true),
+		Entry("default rule ignores drops", DeltaRule{}, 90.0, -70.0, false),
+		Entry("down flags drops", DeltaRule{Direction: DirectionDown, Threshold: 30}, 90.0, -70.0, true),
+		Entry("down ignores increases", DeltaRule{Direction: DirectionDown}, 50.0, 40.0, false),
+		Entry("both flags drops", DeltaRule{Direction: DirectionBoth}, 50.0, -11.0, true),
+		Entry("both flags increases", DeltaRule{Direction: DirectionBoth}, 50.0, 11.0, true),
+		Entry("relative flags small baselines", DeltaRule{Mode: DeltaRelative, Threshold: 100}, 2.0, 9.0, true),
+		Entry("relative ignores high baselines", DeltaRule{Mode: DeltaRelative, Threshold: 100}, 50.0, 9.0, false),
+		Entry("relative skips a zero baseline", DeltaRule{Mode: DeltaRelative}, 0.0, 9.0, false),
+		Entry("min baseline skips quiet hosts", DeltaRule{Mode: DeltaRelative, Threshold: 100, MinBaseline: 20}, 2.0, 9.0, false),
+		Entry("rule threshold overrides the default", DeltaRule{Threshold: 20}, 50.0, 15.0, false),
+	)
+
+	It("should flag a memory drop configured per metric", func() {
+		node := NodeMetric{
+			Name:   "a",
+			MemNow: 20, MemOffset: 90, MemRate: -70,
+			CPUNow: 11, CPUOffset: 2, CPURate: 9,
+			Thresholds: NewThresholds([]DeltaRule{
+				{Metric: MetricMemory, Direction: DirectionDown, Threshold: 30},
+				{Metric: MetricCPU, Mode: DeltaRelative, Threshold: 100, MinBaseline: 20},
+			}),
+		}
+		CheckThresholds(&node)
+
+		Expect(node.MemRateStatus).To(Equal(1))
+		Expect(node.CPURateStatus).To(Equal(0))
+		Expect(node.AbnormalItems()).To(Equal([]string{MetricMemory}))
+	})
+
+	It("should apply disk rules to each filesystem", func() {
+		node := NodeMetric{
+			Filesystems: []FilesystemMetric{
+				{Mountpoint: "/", DiskNow: 10, DiskOffset: 40, DiskRate: -30},
+				{Mountpoint: "/data", DiskNow: 50, DiskOffset: 45, DiskRate: 5},
+			},
+			Thresholds: NewThresholds([]DeltaRule{{Metric: MetricDisk, Direction: DirectionBoth, Threshold: 20}}),
+		}
+		CheckThresholds(&node)
+
+		Expect(node.Filesystems[0].DiskRateStatus).To(Equal(1))
+		Expect(node.Filesystems[1].DiskRateStatus).To(Equal(0))
+		Expect(node.DiskRateStatus).To(Equal(1))
+	})
+
+	It("should describe the configured rules in the report", func() {
+		data := sampleReportData()
+		data.Inspection.Thresholds = NewThresholds([]DeltaRule{
+			{Metric: MetricMemory, Direction: DirectionDown, Threshold: 30},
+			{Metric: MetricCPU, Mode: DeltaRelative, Direction: DirectionBoth, Threshold: 100, MinBaseline: 20},
+		})
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("CPU使用率相对对比值上升或下降超过100%（对比值低于20%时不检查）"))
+		Expect(html).To(ContainSubstring("内存使用率与对比值相比下降超过30个百分点"))
+
+		raw, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var report JSONReport
+		Expect(json.Unmarshal(raw, &report)).To(Succeed())
+		Expect(report.Metadata.DeltaRules).To(Equal([]JSONDeltaRule{
+			{Metric: MetricCPU, Mode: DeltaRelative, Direction: DirectionBoth, Threshold: 100, MinBaseline: 20},
+			{Metric: MetricMemory, Mode: DeltaAbsolute, Direction: DirectionDown, Threshold: 30},
+		}))
+	})
+})
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
+)
+
+// exportColumns 导出文件表头对应的消息key，与HTML报告的表格列保持一致，末尾附加采集状态和原因
+var exportColumns = []string{
+	"column.host",
+	"column.disk", "column.diskOffset", "column.diskRate",
+	"column.inode", "column.inodeOffset", "column.inodeRate",
+	"column.cpu", "column.cpuOffset", "column.cpuRate",
+	"column.memory", "column.memOffset", "column.memRate",
+	"column.status", "column.state", "column.reason",
+}
+
+// exportValueColumns 数值列的数量，数值列紧跟在主机列之后
+const exportValueColumns = 12
+
+// exportStatusColumn 状态列在exportColumns中的下标
+const exportStatusColumn = exportValueColumns + 1
+
+// exportHeader 返回按报告语言翻译后的表头，数值列带百分号单位，对比值列带主对比偏移
+func exportHeader(catalog *i18n.Catalog, offset string) []string {
+	header := make([]string, 0, len(exportColumns))
+	for i, key := range exportColumns {
+		title := catalog.T(key)
+		if strings.HasSuffix(key, "Offset") {
+			title = catalog.T(key, offset)
+		}
+		if i > 0 && i <= exportValueColumns {
+			title += "(%)"
+		}
+		header = append(header, title)
+	}
+	return header
+}
+
+// exportValues 返回主机的数值列，顺序与exportColumns一致，主机不可达时没有有效数值，返回nil
+func exportValues(node NodeMetric) []float64 {
+	if !node.Reachable() {
+		return nil
+	}
+	return []float64{
+		node.DiskNow, node.DiskOffset, node.DiskRate,
+		node.InodeNow, node.InodeOffset, node.InodeRate,
+		node.CPUNow, node.CPUOffset, node.CPURate,
+		node.MemNow, node.MemOffset, node.MemRate,
+	}
+}
+
+// exportMetrics 数值列所属的指标，每项指标依次为当前值、对比值和差值三列
+var exportMetrics = []string{MetricDisk, MetricInode, MetricCPU, MetricMemory}
+
+// exportStatuses 返回主机数值列的状态，顺序与exportValues一致，对比值列没有状态
+func exportStatuses(node NodeMetric) []int {
+	return []int{
+		node.DiskNowStatus, 0, node.DiskRateStatus,
+		node.InodeNowStatus, 0, node.InodeRateStatus,
+		node.CPUNowStatus, 0, node.CPURateStatus,
+		node.MemNowStatus, 0, node.MemRateStatus,
+	}
+}
+
+// exportStatus 返回主机的状态、采集状态和原因列
+func exportStatus(catalog *i18n.Catalog, node NodeMetric) []string {
+	state := node.Health.State
+	if state == "" {
+		state = NodeStateOK
+	}
+	return []string{statusText(catalog, node.Status), catalog.T("state." + state), node.Health.Reason}
+}
+
+// GenerateCSV 将主机指标导出为CSV
+func GenerateCSV(data *ReportData) ([]byte, error) {
+	var buf bytes.Buffer
+	// 写入UTF-8 BOM，避免Excel打开中文表头时乱码
+	buf.WriteString("\ufeff")
+
+	catalog := data.Catalog()
+	w := csv.NewWriter(&buf)
+	if err := w.Write(exportHeader(catalog, data.ComparisonOffset())); err != nil {
+		return nil, fmt.Errorf("写入CSV表头失败: %w", err)
+	}
+
+	for _, node := range data.Inspection.Node {
+		record := make([]string, 1, len(exportColumns))
+		record[0] = node.Name
+		values := exportValues(node)
+		for i := 0; i < exportValueColumns; i++ {
+			// 主机不可达时数值列留空，避免被误读为0
+			value := ""
+			if values != nil {
+				value = strconv.FormatFloat(values[i], 'f', -1, 64)
+			}
+			record = append(record, value)
+		}
+		record = append(record, exportStatus(catalog, node)...)
+
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("写入CSV数据失败: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("生成CSV失败: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,216 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// xlsxWorksheet 测试中解析的工作表内容
+type xlsxWorksheet struct {
+	Dimension struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"dimension"`
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			S      int    `xml:"s,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+	AutoFilter struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"autoFilter"`
+	ConditionalFormatting []struct {
+		Sqref string `xml:"sqref,attr"`
+		Rules []struct {
+			DxfID    int    `xml:"dxfId,attr"`
+			Operator string `xml:"operator,attr"`
+			Formula  string `xml:"formula"`
+		} `xml:"cfRule"`
+	} `xml:"conditionalFormatting"`
+}
+
+// cellValue 返回单元格的值，内联字符串返回文本
+func (s xlsxWorksheet) cellValue(ref string) string {
+	for _, row := range s.Rows {
+		for _, cell := range row.Cells {
+			if cell.R == ref {
+				if cell.T == "inlineStr" {
+					return cell.Inline
+				}
+				return cell.V
+			}
+		}
+	}
+	return ""
+}
+
+// cellStyle 返回单元格的样式下标，单元格不存在时返回-1
+func (s xlsxWorksheet) cellStyle(ref string) int {
+	for _, row := range s.Rows {
+		for _, cell := range row.Cells {
+			if cell.R == ref {
+				return cell.S
+			}
+		}
+	}
+	return -1
+}
+
+// readZipFile 读取压缩包中的文件
+func readZipFile(archive *zip.Reader, name string) []byte {
+	file, err := archive.Open(name)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	defer func() { _ = file.Close() }()
+	content, err := io.ReadAll(file)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return content
+}
+
+var _ = Describe("Exports", func() {
+	Context("When generating CSV", func() {
+		It("should write the header and one row per node", func() {
+			output, err := GenerateCSV(sampleReportData())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(HavePrefix("\ufeff"))
+
+			records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(output), "\ufeff"))).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([][]string{
+				{
+					"主机IP",
+					"硬盘使用率(%)", "硬盘使用率前24h(%)", "硬盘使用率差值(%)",
+					"inode使用率(%)", "inode使用率前24h(%)", "inode使用率差值(%)",
+					"CPU使用率(%)", "CPU使用率前24h(%)", "CPU使用率差值(%)",
+					"内存使用率(%)", "内存使用率前24h(%)", "内存使用率差值(%)",
+					"状态", "采集状态", "原因",
+				},
+				{"192.168.0.1:9100", "45.5", "44.1", "1.4", "12.3", "12.3", "0", "23.45", "20.1", "3.35", "61.2", "60.8", "0.4", "正常", "正常", ""},
+				{"192.168.0.2:9100", "91.2", "78.6", "12.6", "30", "29.5", "0.5", "72.8", "40.3", "32.5", "85.1", "84.9", "0.2", "异常", "正常", ""},
+			}))
+		})
+
+		It("should leave the values of unreachable nodes empty", func() {
+			data := sampleReportData()
+			data.Inspection.Node = append(data.Inspection.Node,
+				NewUnreachableNode("192.168.0.9:9100", NodeHealth{State: NodeStateDown, Reason: "up=0"}))
+			output, err := GenerateCSV(data)
+			Expect(err).NotTo(HaveOccurred())
+
+			records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(output), "\ufeff"))).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(4))
+			Expect(records[3]).To(Equal([]string{"192.168.0.9:9100", "", "", "", "", "", "", "", "", "", "", "", "", "异常", "宕机", "up=0"}))
+		})
+	})
+
+	Context("When generating XLSX", func() {
+		var (
+			archive *zip.Reader
+			sheet   xlsxWorksheet
+		)
+
+		BeforeEach(func() {
+			output, err := GenerateXLSX(sampleReportData())
+			Expect(err).NotTo(HaveOccurred())
+			archive, err = zip.NewReader(bytes.NewReader(output), int64(len(output)))
+			Expect(err).NotTo(HaveOccurred())
+			sheet = xlsxWorksheet{}
+			Expect(xml.Unmarshal(readZipFile(archive, "xl/worksheets/sheet1.xml"), &sheet)).To(Succeed())
+		})
+
+		It("should contain every part of the workbook", func() {
+			names := make([]string, 0, len(archive.File))
+			for _, file := range archive.File {
+				names = append(names, file.Name)
+			}
+			Expect(names).To(ConsistOf(
+				"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+				"xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml",
+			))
+			Expect(string(readZipFile(archive, "xl/workbook.xml"))).To(ContainSubstring(`<sheet name="巡检结果"`))
+		})
+
+		It("should write the header and node values", func() {
+			Expect(sheet.Dimension.Ref).To(Equal("A1:P3"))
+			Expect(sheet.AutoFilter.Ref).To(Equal("A1:P3"))
+			Expect(sheet.Rows).To(HaveLen(3))
+
+			Expect(sheet.cellValue("A1")).To(Equal("主机IP"))
+			Expect(sheet.cellValue("C1")).To(Equal("硬盘使用率前24h(%)"))
+			Expect(sheet.cellValue("N1")).To(Equal("状态"))
+			Expect(sheet.cellValue("O1")).To(Equal("采集状态"))
+			Expect(sheet.cellValue("P1")).To(Equal("原因"))
+			Expect(sheet.Rows[0].Cells[0].S).To(Equal(1))
+
+			Expect(sheet.cellValue("A3")).To(Equal("192.168.0.2:9100"))
+			Expect(sheet.cellValue("B3")).To(Equal("91.2"))
+			Expect(sheet.cellValue("J3")).To(Equal("32.5"))
+			Expect(sheet.cellValue("N2")).To(Equal("正常"))
+			Expect(sheet.cellValue("N3")).To(Equal("异常"))
+			Expect(sheet.cellValue("O3")).To(Equal("正常"))
+		})
+
+		It("should not write values for unreachable nodes", func() {
+			data := sampleReportData()
+			data.Inspection.Node = append(data.Inspection.Node,
+				NewUnreachableNode("192.168.0.9:9100", NodeHealth{State: NodeStateUnknown, Reason: "查询CPU使用率失败"}))
+			output, err := GenerateXLSX(data)
+			Expect(err).NotTo(HaveOccurred())
+			archive, err = zip.NewReader(bytes.NewReader(output), int64(len(output)))
+			Expect(err).NotTo(HaveOccurred())
+			sheet = xlsxWorksheet{}
+			Expect(xml.Unmarshal(readZipFile(archive, "xl/worksheets/sheet1.xml"), &sheet)).To(Succeed())
+
+			Expect(sheet.Rows).To(HaveLen(4))
+			Expect(sheet.Rows[3].Cells).To(HaveLen(4))
+			Expect(sheet.cellValue("A4")).To(Equal("192.168.0.9:9100"))
+			Expect(sheet.cellValue("B4")).To(BeEmpty())
+			Expect(sheet.cellValue("N4")).To(Equal("异常"))
+			Expect(sheet.cellValue("O4")).To(Equal("未知"))
+			Expect(sheet.cellValue("P4")).To(Equal("查询CPU使用率失败"))
+		})
+
+		It("should color the status column", func() {
+			Expect(sheet.ConditionalFormatting).To(HaveLen(1))
+			status := sheet.ConditionalFormatting[0]
+			Expect(status.Sqref).To(Equal("N2:N3"))
+			Expect(status.Rules).To(HaveLen(2))
+			Expect(status.Rules[0].Formula).To(Equal(`"异常"`))
+			Expect(status.Rules[0].DxfID).To(Equal(0))
+			Expect(status.Rules[1].Formula).To(Equal(`"正常"`))
+			Expect(status.Rules[1].DxfID).To(Equal(1))
+		})
+
+		It("should style values by the statuses computed for each node", func() {
+			data := sampleReportData()
+			// 模拟主机级阈值覆盖：第一台主机的CPU使用率低于全局阈值仍判定为异常，第二台主机的硬盘使用率高于全局阈值仍为正常
+			data.Inspection.Node[0].CPUNowStatus = 1
+			data.Inspection.Node[1].DiskNowStatus = 0
+			data.Inspection.Node[1].Acknowledged = []Acknowledgement{{Metric: MetricCPU}}
+			output, err := GenerateXLSX(data)
+			Expect(err).NotTo(HaveOccurred())
+			archive, err = zip.NewReader(bytes.NewReader(output), int64(len(output)))
+			Expect(err).NotTo(HaveOccurred())
+			sheet = xlsxWorksheet{}
+			Expect(xml.Unmarshal(readZipFile(archive, "xl/worksheets/sheet1.xml"), &sheet)).To(Succeed())
+
+			Expect(sheet.cellStyle("H2")).To(Equal(3))
+			Expect(sheet.cellStyle("B2")).To(Equal(2))
+			Expect(sheet.cellStyle("B3")).To(Equal(2))
+			Expect(sheet.cellStyle("C3")).To(Equal(2))
+			Expect(sheet.cellStyle("D3")).To(Equal(3))
+			Expect(sheet.cellStyle("J3")).To(Equal(4))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// FilesystemMetric 单个分区的硬盘和inode使用率
+type FilesystemMetric struct {
+	Device     string
+	Mountpoint string
+	FSType     string
+	// 硬盘使用率
+	DiskNow        float64
+	DiskOffset     float64
+	DiskRate       float64
+	DiskNowStatus  int
+	DiskRateStatus int
+	// Inode使用率
+	InodeNow        float64
+	InodeOffset     float64
+	InodeRate       float64
+	InodeNowStatus  int
+	InodeRateStatus int
+	// 该分区生效的阈值，为0时使用主机生效的硬盘和inode使用率阈值
+	DiskThreshold  float64
+	InodeThreshold float64
+	// 分区整体状态
+	Status int
+}
+
+// FilesystemThreshold 按挂载点覆盖硬盘和inode使用率阈值，值为0表示使用主机生效的阈值
+type FilesystemThreshold struct {
+	// 挂载点正则表达式，需完整匹配
+	Mountpoint string
+	Disk       float64
+	Inode      float64
+}
+
+// ApplyFilesystemThresholds 为每个分区设置生效的阈值，按顺序使用第一条匹配挂载点的规则；
+// 未匹配的阈值保持为0，检查时使用主机生效的阈值
+func ApplyFilesystemThresholds(filesystems []FilesystemMetric, rules []FilesystemThreshold) error {
+	patterns := make([]*regexp.Regexp, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := regexp.Compile("^(?:" + rule.Mountpoint + ")$")
+		if err != nil {
+			return fmt.Errorf("挂载点规则%q无效: %w", rule.Mountpoint, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	for i := range filesystems {
+		fs := &filesystems[i]
+		fs.DiskThreshold = 0
+		fs.InodeThreshold = 0
+		for j, pattern := range patterns {
+			if !pattern.MatchString(fs.Mountpoint) {
+				continue
+			}
+			if rules[j].Disk > 0 {
+				fs.DiskThreshold = rules[j].Disk
+			}
+			if rules[j].Inode > 0 {
+				fs.InodeThreshold = rules[j].Inode
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// checkFilesystems 按分区检查硬盘和inode使用率，任一分区异常时主机对应指标异常
+func checkFilesystems(node *NodeMetric, rateThreshold float64) {
+	for i := range node.Filesystems {
+		fs := &node.Filesystems[i]
+
+		if fs.DiskThreshold == 0 {
+			fs.DiskThreshold = node.Thresholds.usage(MetricDisk)
+		}
+		if fs.InodeThreshold == 0 {
+			fs.InodeThreshold = node.Thresholds.usage(MetricInode)
+		}
+
+		if fs.DiskNow > fs.DiskThreshold {
+			fs.DiskNowStatus = 1
+			node.DiskNowStatus = 1
+		}
+		if node.deltaExceeded(MetricDisk, fs.DiskOffset, fs.DiskRate, rateThreshold) {
+			fs.DiskRateStatus = 1
+			node.DiskRateStatus = 1
+		}
+		if fs.InodeNow > fs.InodeThreshold {
+			fs.InodeNowStatus = 1
+			node.InodeNowStatus = 1
+		}
+		if node.deltaExceeded(MetricInode, fs.InodeOffset, fs.InodeRate, rateThreshold) {
+			fs.InodeRateStatus = 1
+			node.InodeRateStatus = 1
+		}
+
+		if fs.DiskNowStatus == 1 || fs.DiskRateStatus == 1 || fs.InodeNowStatus == 1 || fs.InodeRateStatus == 1 {
+			fs.Status = 1
+			node.Status = 1
+		}
+	}
+}
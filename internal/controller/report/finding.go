@@ -0,0 +1,154 @@
+package report
+
+import "sort"
+
+// 巡检结果的严重程度，数值越大越严重，便于告警规则按阈值筛选
+const (
+	// SeverityNormal 指标正常
+	SeverityNormal = 0
+	// SeverityAcknowledged 异常已被静默规则确认
+	SeverityAcknowledged = 1
+	// SeverityWarning 指标异常，或主机可达但指标采集失败
+	SeverityWarning = 2
+	// SeverityCritical 主机宕机或数据过期
+	SeverityCritical = 3
+)
+
+// DefaultFindingMaxNodes 未配置时导出巡检结果的主机数上限
+const DefaultFindingMaxNodes = 200
+
+// Finding 主机单项指标的巡检结果
+type Finding struct {
+	Node   string
+	Metric string
+	// 当前值：使用率为百分比，forecast为预计写满天数，reachability可达为1、不可达为0
+	Value float64
+	// 主对比偏移的对比值和当前值与对比值之差，只有使用率指标有效
+	Comparison float64
+	Delta      float64
+	Compared   bool
+	// 动态基线的均值，只有历史数据足够计算基线的使用率指标有效
+	BaselineMean float64
+	HasBaseline  bool
+	// 严重程度，见Severity*常量
+	Severity int
+}
+
+// FindingFilter 控制导出的巡检结果数量
+type FindingFilter struct {
+	// 导出的指标，为空时导出全部
+	Metrics []string
+	// 只导出存在异常或已确认异常的主机
+	AbnormalOnly bool
+	// 导出的主机数上限，超过时优先保留严重程度高的主机，为0时不限制
+	MaxNodes int
+}
+
+// Findings 返回主机各项指标的巡检结果，不可达的主机只有reachability一项
+func (n NodeMetric) Findings() []Finding {
+	acknowledged := make(map[string]bool, len(n.Acknowledged))
+	for _, ack := range n.Acknowledged {
+		acknowledged[ack.Metric] = true
+	}
+	abnormal := make(map[string]bool)
+	for _, metric := range n.AbnormalItems() {
+		abnormal[metric] = true
+	}
+	severity := func(metric string, level int) int {
+		switch {
+		case !abnormal[metric]:
+			return SeverityNormal
+		case acknowledged[metric]:
+			return SeverityAcknowledged
+		}
+		return level
+	}
+
+	if !n.Reachable() {
+		level := SeverityWarning
+		if n.Critical() {
+			level = SeverityCritical
+		}
+		return []Finding{{Node: n.Name, Metric: MetricReachability, Severity: severity(MetricReachability, level)}}
+	}
+
+	findings := []Finding{{Node: n.Name, Metric: MetricReachability, Value: 1}}
+	usage := []struct {
+		metric                 string
+		value, previous, delta float64
+	}{
+		{MetricDisk, n.DiskNow, n.DiskOffset, n.DiskRate},
+		{MetricInode, n.InodeNow, n.InodeOffset, n.InodeRate},
+		{MetricCPU, n.CPUNow, n.CPUOffset, n.CPURate},
+		{MetricMemory, n.MemNow, n.MemOffset, n.MemRate},
+	}
+	for _, item := range usage {
+		finding := Finding{
+			Node:       n.Name,
+			Metric:     item.metric,
+			Value:      item.value,
+			Comparison: item.previous,
+			Delta:      item.delta,
+			Compared:   true,
+			Severity:   severity(item.metric, SeverityWarning),
+		}
+		if baseline, ok := n.BaselineOf(item.metric); ok {
+			finding.BaselineMean, finding.HasBaseline = baseline.Mean, true
+		}
+		findings = append(findings, finding)
+	}
+	if n.DiskForecast != nil {
+		findings = append(findings, Finding{
+			Node:     n.Name,
+			Metric:   MetricForecast,
+			Value:    n.DiskForecast.Days,
+			Severity: severity(MetricForecast, SeverityWarning),
+		})
+	}
+	return findings
+}
+
+// Findings 按过滤条件返回报告中各主机的巡检结果，第二个返回值为超过上限未导出的主机数
+func (d *ReportData) Findings(filter FindingFilter) ([]Finding, int) {
+	metrics := make(map[string]bool, len(filter.Metrics))
+	for _, metric := range filter.Metrics {
+		metrics[metric] = true
+	}
+
+	type nodeFindings struct {
+		findings []Finding
+		severity int
+	}
+	var nodes []nodeFindings
+	for _, node := range d.Inspection.Node {
+		if filter.AbnormalOnly && node.Status == 0 {
+			continue
+		}
+		var item nodeFindings
+		for _, finding := range node.Findings() {
+			if len(metrics) > 0 && !metrics[finding.Metric] {
+				continue
+			}
+			item.findings = append(item.findings, finding)
+			item.severity = max(item.severity, finding.Severity)
+		}
+		if len(item.findings) > 0 {
+			nodes = append(nodes, item)
+		}
+	}
+
+	dropped := 0
+	if filter.MaxNodes > 0 && len(nodes) > filter.MaxNodes {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return nodes[i].severity > nodes[j].severity
+		})
+		dropped = len(nodes) - filter.MaxNodes
+		nodes = nodes[:filter.MaxNodes]
+	}
+
+	var findings []Finding
+	for _, node := range nodes {
+		findings = append(findings, node.findings...)
+	}
+	return findings, dropped
+}
@@ -0,0 +1,88 @@
+package report
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Findings", func() {
+	It("should report value, comparison, delta and severity per metric", func() {
+		data := sampleReportData()
+		Expect(ApplySilences(data, []Silence{
+			{Node: "192.168.0.2:9100", Metric: MetricMemory, Reason: "缓存", Owner: "ops", ExpiresAt: time.Now().Add(time.Hour)},
+		}, time.Now())).To(Succeed())
+
+		findings := data.Inspection.Node[1].Findings()
+		Expect(findings).To(HaveLen(5))
+		Expect(findings[0]).To(Equal(Finding{Node: "192.168.0.2:9100", Metric: MetricReachability, Value: 1}))
+		Expect(findings[1]).To(Equal(Finding{
+			Node: "192.168.0.2:9100", Metric: MetricDisk,
+			Value: 91.2, Comparison: 78.6, Delta: 12.6, Compared: true,
+			Severity: SeverityWarning,
+		}))
+		Expect(findings[2].Severity).To(Equal(SeverityNormal))
+		Expect(findings[4].Metric).To(Equal(MetricMemory))
+		Expect(findings[4].Severity).To(Equal(SeverityAcknowledged))
+	})
+
+	It("should report the baseline mean when there is enough history", func() {
+		node := sampleReportData().Inspection.Node[0]
+		node.Baselines = map[string]Baseline{
+			MetricCPU:    NewBaseline(repeat(24, 20, 22)),
+			MetricMemory: NewBaseline(repeat(MinBaselineSamples-1, 60)),
+		}
+		CheckBaseline(&node, 3)
+
+		findings := node.Findings()
+		Expect(findings[3].Metric).To(Equal(MetricCPU))
+		Expect(findings[3].HasBaseline).To(BeTrue())
+		Expect(findings[3].BaselineMean).To(Equal(21.0))
+		Expect(findings[3].Comparison).To(Equal(20.1))
+		Expect(findings[4].Metric).To(Equal(MetricMemory))
+		Expect(findings[4].HasBaseline).To(BeFalse())
+	})
+
+	It("should report unreachable nodes by reachability only", func() {
+		down := NewUnreachableNode("10.0.0.9:9100", NodeHealth{State: NodeStateDown})
+		Expect(down.Findings()).To(Equal([]Finding{
+			{Node: "10.0.0.9:9100", Metric: MetricReachability, Severity: SeverityCritical},
+		}))
+
+		unknown := NewUnreachableNode("10.0.0.9:9100", NodeHealth{State: NodeStateUnknown})
+		Expect(unknown.Findings()[0].Severity).To(Equal(SeverityWarning))
+	})
+
+	It("should include the forecast when the disk is filling up", func() {
+		node := NodeMetric{Name: "10.0.0.1:9100", DiskForecast: &DiskForecast{Mountpoint: "/data", Days: 3}}
+		CheckForecast(&node, 7)
+		findings := node.Findings()
+		Expect(findings[len(findings)-1]).To(Equal(Finding{
+			Node: "10.0.0.1:9100", Metric: MetricForecast, Value: 3, Severity: SeverityWarning,
+		}))
+	})
+
+	It("should filter metrics and abnormal nodes", func() {
+		data := sampleReportData()
+		findings, dropped := data.Findings(FindingFilter{Metrics: []string{MetricCPU}, AbnormalOnly: true})
+		Expect(dropped).To(Equal(0))
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Node).To(Equal("192.168.0.2:9100"))
+		Expect(findings[0].Metric).To(Equal(MetricCPU))
+	})
+
+	It("should keep the most severe nodes within the limit", func() {
+		data := sampleReportData()
+		data.Inspection.Node = append(data.Inspection.Node,
+			NewUnreachableNode("10.0.0.9:9100", NodeHealth{State: NodeStateStale}))
+
+		findings, dropped := data.Findings(FindingFilter{MaxNodes: 2})
+		Expect(dropped).To(Equal(1))
+		nodes := map[string]bool{}
+		for _, finding := range findings {
+			nodes[finding.Node] = true
+		}
+		Expect(nodes).To(Equal(map[string]bool{"10.0.0.9:9100": true, "192.168.0.2:9100": true}))
+	})
+})
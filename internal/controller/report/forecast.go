@@ -0,0 +1,41 @@
+package report
+
+// 磁盘写满预测的默认配置
+const (
+	// DefaultForecastWindow 线性回归使用的默认时间窗口
+	DefaultForecastWindow = "24h"
+	// ForecastThreshold 预计写满天数阈值
+	ForecastThreshold = 7
+)
+
+// MetricForecast 磁盘写满预测，对应消息目录中的metric.forecast
+const MetricForecast = "forecast"
+
+// ForecastConfig 写满预测配置，为nil时报告中不展示预测列
+type ForecastConfig struct {
+	// 线性回归使用的时间窗口，如24h
+	Window string
+	// 预计写满天数低于该值时标记为异常
+	ThresholdDays float64
+}
+
+// DiskForecast 主机磁盘写满预测结果
+type DiskForecast struct {
+	// 预计最先写满的分区
+	Mountpoint string
+	// 按当前增长速度预计写满的天数
+	Days float64
+}
+
+// CheckForecast 检查磁盘写满预测并设置状态，
+// 即使使用率低于静态阈值，写满速度过快的磁盘同样标记为异常
+func CheckForecast(node *NodeMetric, thresholdDays float64) {
+	if node.DiskForecast == nil {
+		return
+	}
+
+	if node.DiskForecast.Days < thresholdDays {
+		node.DiskFullStatus = 1
+		node.Status = 1
+	}
+}
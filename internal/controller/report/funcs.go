@@ -0,0 +1,220 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
+)
+
+// statusText 返回主机状态的展示文本
+func statusText(catalog *i18n.Catalog, status int) string {
+	switch status {
+	case 1:
+		return catalog.T("status.abnormal")
+	case StatusAcknowledged:
+		return catalog.T("status.acknowledged")
+	}
+	return catalog.T("status.normal")
+}
+
+// nodeStatusText 返回主机状态的展示文本，无法采集指标的主机展示其采集状态
+func nodeStatusText(catalog *i18n.Catalog, node NodeMetric) string {
+	if !node.Reachable() {
+		return catalog.T("state." + node.Health.State)
+	}
+	return statusText(catalog, node.Status)
+}
+
+// changeText 返回异常变化的展示文本，如 CPU使用率 持续2天
+func changeText(catalog *i18n.Catalog, change Change) (string, error) {
+	metric := catalog.T("metric." + change.Metric)
+	if change.Kind != ChangeOngoing {
+		return catalog.T("change."+change.Kind, metric), nil
+	}
+	duration, err := formatDuration(catalog, change.Duration)
+	if err != nil {
+		return "", err
+	}
+	return catalog.T("change.ongoing", metric, duration), nil
+}
+
+// acknowledgementText 返回被确认的异常的展示文本，如 硬盘使用率 已确认: 归档盘（张三，至 2026-12-01）
+func acknowledgementText(catalog *i18n.Catalog, ack Acknowledgement) string {
+	return catalog.T("silence.acknowledged", catalog.T("metric."+ack.Metric), ack.Silence.Reason,
+		ack.Silence.Owner, catalog.FormatDate(ack.Silence.ExpiresAt))
+}
+
+// deltaRuleText 返回差值规则的说明，规则未配置阈值时使用defaultThreshold
+func deltaRuleText(catalog *i18n.Catalog, rule DeltaRule, defaultThreshold float64) string {
+	threshold := rule.Threshold
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+	direction := rule.Direction
+	if direction == "" {
+		direction = DirectionUp
+	}
+
+	key := "delta.absolute"
+	if rule.Mode == DeltaRelative {
+		key = "delta.relative"
+	}
+	text := catalog.T(key, catalog.T("metric."+rule.Metric), catalog.T("delta.direction."+direction),
+		strconv.FormatFloat(threshold, 'f', -1, 64))
+	if rule.MinBaseline > 0 {
+		text += catalog.T("delta.baseline", strconv.FormatFloat(rule.MinBaseline, 'f', -1, 64))
+	}
+	return text
+}
+
+// trendName 返回趋势图和热力图中指标的名称，硬盘和inode的趋势为各分区使用率的最大值
+func trendName(catalog *i18n.Catalog, metric string) string {
+	name := catalog.T("metric." + metric)
+	if metric == MetricDisk || metric == MetricInode {
+		return catalog.T("trends.maxFilesystem", name)
+	}
+	return name
+}
+
+// overrideText 返回主机生效的阈值覆盖说明，如 阈值覆盖 group:db: 硬盘 90%、inode 60%、CPU 80%、内存 90%；
+// 未使用覆盖规则时返回空字符串
+func overrideText(catalog *i18n.Catalog, thresholds Thresholds) string {
+	if len(thresholds.Sources) == 0 {
+		return ""
+	}
+	limits := make([]string, 0, 4)
+	for _, item := range []struct{ key, metric string }{
+		{"metric.short.disk", MetricDisk},
+		{"metric.short.inode", MetricInode},
+		{"metric.short.cpu", MetricCPU},
+		{"metric.short.mem", MetricMemory},
+	} {
+		limits = append(limits, catalog.T(item.key)+" "+formatPercent(thresholds.usage(item.metric)))
+	}
+	return catalog.T("override.applied", strings.Join(thresholds.Sources, ", "),
+		strings.Join(limits, catalog.T("list.separator")))
+}
+
+// formatNumber 按指定小数位数格式化数值
+func formatNumber(value float64, precision int) string {
+	return strconv.FormatFloat(value, 'f', precision, 64)
+}
+
+// formatPercent 将数值格式化为百分比
+func formatPercent(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64) + "%"
+}
+
+// formatDelta 格式化差值，正数带加号
+func formatDelta(value float64) string {
+	s := strconv.FormatFloat(value, 'f', -1, 64)
+	if value >= 0 {
+		return "+" + s
+	}
+	return s
+}
+
+// formatMetric 格式化当前值及差值，如 45.5% (+1.4)
+func formatMetric(now, rate float64) string {
+	return fmt.Sprintf("%s (%s)", formatPercent(now), formatDelta(rate))
+}
+
+// formatDuration 将时长格式化为易读的描述，支持time.Duration和以秒为单位的数值
+func formatDuration(catalog *i18n.Catalog, value interface{}) (string, error) {
+	var d time.Duration
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+	case float64:
+		d = time.Duration(v * float64(time.Second))
+	case int:
+		d = time.Duration(v) * time.Second
+	case int64:
+		d = time.Duration(v) * time.Second
+	default:
+		return "", fmt.Errorf("不支持的时长类型: %T", value)
+	}
+
+	if d < time.Minute {
+		return catalog.T("duration.seconds", int(d.Seconds())), nil
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, catalog.T("duration.days", days))
+	}
+	if hours > 0 {
+		parts = append(parts, catalog.T("duration.hours", hours))
+	}
+	if minutes > 0 && days == 0 {
+		parts = append(parts, catalog.T("duration.minutes", minutes))
+	}
+	return strings.Join(parts, catalog.T("duration.separator")), nil
+}
+
+// commonFuncs 返回HTML与文本模板共用的函数，与语言相关的函数绑定到catalog
+func commonFuncs(catalog *i18n.Catalog) map[string]interface{} {
+	status := func(status int) string {
+		return statusText(catalog, status)
+	}
+	metricNames := func(names []string) string {
+		translated := make([]string, 0, len(names))
+		for _, name := range names {
+			translated = append(translated, catalog.T("metric."+name))
+		}
+		return strings.Join(translated, catalog.T("list.separator"))
+	}
+
+	return map[string]interface{}{
+		"t":          catalog.T,
+		"date":       catalog.FormatDate,
+		"timestamp":  catalog.FormatTimestamp,
+		"statusText": status,
+		"nodeStatus": func(node NodeMetric) string {
+			return nodeStatusText(catalog, node)
+		},
+		"change": func(change Change) (string, error) {
+			return changeText(catalog, change)
+		},
+		"offsets": func(comparisons []Comparison) string {
+			offsets := make([]string, 0, len(comparisons))
+			for _, comparison := range comparisons {
+				offsets = append(offsets, comparison.Offset)
+			}
+			return strings.Join(offsets, catalog.T("list.separator"))
+		},
+		"deltaRule": func(rule DeltaRule, defaultThreshold float64) string {
+			return deltaRuleText(catalog, rule, defaultThreshold)
+		},
+		"override": func(thresholds Thresholds) string {
+			return overrideText(catalog, thresholds)
+		},
+		"acknowledgement": func(ack Acknowledgement) string {
+			return acknowledgementText(catalog, ack)
+		},
+		"severity":   status,
+		"metricName": func(name string) string { return catalog.T("metric." + name) },
+		"metricList": metricNames,
+		"number":     formatNumber,
+		"percent":    formatPercent,
+		"delta":      formatDelta,
+		"sigma":      func(value float64) string { return formatDelta(value) + "σ" },
+		"metric":     formatMetric,
+		"trendName": func(metric string) string {
+			return trendName(catalog, metric)
+		},
+		"days": func(days float64) string {
+			return catalog.T("forecast.days", formatNumber(days, 1))
+		},
+		"duration": func(value interface{}) (string, error) {
+			return formatDuration(catalog, value)
+		},
+	}
+}
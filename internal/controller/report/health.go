@@ -0,0 +1,58 @@
+package report
+
+import "time"
+
+// 主机采集状态，对应消息目录中的state.<state>
+const (
+	// NodeStateOK 指标采集成功
+	NodeStateOK = "ok"
+	// NodeStateDown up指标为0，Prometheus无法抓取该主机
+	NodeStateDown = "down"
+	// NodeStateStale 最近一次抓取距今超过允许的时长
+	NodeStateStale = "stale"
+	// NodeStateUnknown 主机可达但指标采集失败
+	NodeStateUnknown = "unknown"
+)
+
+// MetricReachability 主机可达性，对应消息目录中的metric.reachability
+const MetricReachability = "reachability"
+
+// NodeHealth 主机的抓取状态
+type NodeHealth struct {
+	// 采集状态，见NodeState*常量
+	State string
+	// 最近一次抓取距今的时长，没有抓取记录时为0
+	ScrapeAge time.Duration
+	// 不可达或采集失败的原因
+	Reason string
+}
+
+// NewUnreachableNode 构造无法采集指标的主机，报告中展示为严重或未知
+func NewUnreachableNode(name string, health NodeHealth) NodeMetric {
+	return NodeMetric{
+		Name:   name,
+		Health: health,
+		Status: 1,
+	}
+}
+
+// Reachable 主机指标是否采集成功
+func (n NodeMetric) Reachable() bool {
+	return n.Health.State == "" || n.Health.State == NodeStateOK
+}
+
+// Critical 主机是否宕机或数据过期
+func (n NodeMetric) Critical() bool {
+	return n.Health.State == NodeStateDown || n.Health.State == NodeStateStale
+}
+
+// UnreachableNodes 返回无法采集指标的主机
+func (d *ReportData) UnreachableNodes() []NodeMetric {
+	var nodes []NodeMetric
+	for _, node := range d.Inspection.Node {
+		if !node.Reachable() {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"sort"
+	"time"
+)
+
+// 异常项相对上次巡检的变化，对应消息目录中的change.<kind>
+const (
+	// ChangeNew 本次新出现的异常
+	ChangeNew = "new"
+	// ChangeOngoing 上次巡检时已存在的异常
+	ChangeOngoing = "ongoing"
+	// ChangeResolved 上次巡检时存在、本次已恢复的异常
+	ChangeResolved = "resolved"
+)
+
+// Snapshot 一次巡检的结果，持久化后作为下次巡检的对比基准
+type Snapshot struct {
+	// 巡检时间
+	GeneratedAt time.Time `json:"generatedAt"`
+	// 各主机的结果，key为主机名称
+	Nodes map[string]NodeSnapshot `json:"nodes"`
+}
+
+// NodeSnapshot 单个主机的巡检结果
+type NodeSnapshot struct {
+	Status int    `json:"status"`
+	State  string `json:"state,omitempty"`
+	// 异常指标及其首次出现的时间，key为指标名称
+	Findings map[string]time.Time `json:"findings,omitempty"`
+}
+
+// Change 主机某项指标相对上次巡检的变化
+type Change struct {
+	Node   string
+	Metric string
+	// 变化类型，见Change*常量
+	Kind string
+	// 异常首次出现的时间
+	Since time.Time
+	// 异常已持续的时长，截至本次巡检
+	Duration time.Duration
+}
+
+// ChangeSet 本次巡检相对上次巡检的变化汇总
+type ChangeSet struct {
+	// 上次巡检时间
+	Previous time.Time
+	New      []Change
+	Ongoing  []Change
+	Resolved []Change
+}
+
+// Empty 是否没有任何异常变化
+func (c *ChangeSet) Empty() bool {
+	return len(c.New) == 0 && len(c.Ongoing) == 0 && len(c.Resolved) == 0
+}
+
+// CompareHistory 将本次巡检结果与上次的快照对比，为主机标记新增、持续和已恢复的异常，
+// 并返回本次巡检的快照。previous为nil（首次巡检）时不做对比。
+//
+// 本次不可达的主机无法判断指标是否恢复，上次的指标异常原样保留到快照中；
+// 本次不再巡检的主机不计为恢复。
+func CompareHistory(data *ReportData, previous *Snapshot) *Snapshot {
+	now := data.Metadata.GeneratedAt
+	snapshot := &Snapshot{
+		GeneratedAt: now,
+		Nodes:       make(map[string]NodeSnapshot, len(data.Inspection.Node)),
+	}
+
+	var changes *ChangeSet
+	if previous != nil {
+		changes = &ChangeSet{Previous: previous.GeneratedAt}
+	}
+
+	for i := range data.Inspection.Node {
+		node := &data.Inspection.Node[i]
+		last, seen := NodeSnapshot{}, false
+		if previous != nil {
+			last, seen = previous.Nodes[node.Name]
+		}
+
+		current := NodeSnapshot{
+			Status:   node.Status,
+			State:    node.Health.State,
+			Findings: make(map[string]time.Time),
+		}
+		node.Changes = nil
+
+		for _, metric := range node.AbnormalItems() {
+			since, ongoing := last.Findings[metric]
+			if !ongoing {
+				since = now
+			}
+			current.Findings[metric] = since
+
+			if changes == nil {
+				continue
+			}
+			change := Change{Node: node.Name, Metric: metric, Kind: ChangeNew, Since: since, Duration: now.Sub(since)}
+			if ongoing {
+				change.Kind = ChangeOngoing
+				changes.Ongoing = append(changes.Ongoing, change)
+			} else {
+				changes.New = append(changes.New, change)
+			}
+			node.Changes = append(node.Changes, change)
+		}
+
+		if seen {
+			for metric, since := range last.Findings {
+				if _, ok := current.Findings[metric]; ok {
+					continue
+				}
+				if !node.Reachable() && metric != MetricReachability {
+					current.Findings[metric] = since
+					continue
+				}
+				change := Change{Node: node.Name, Metric: metric, Kind: ChangeResolved, Since: since, Duration: now.Sub(since)}
+				changes.Resolved = append(changes.Resolved, change)
+				node.Changes = append(node.Changes, change)
+			}
+		}
+
+		if len(current.Findings) == 0 {
+			current.Findings = nil
+		}
+		snapshot.Nodes[node.Name] = current
+		sortChanges(node.Changes)
+	}
+
+	if changes != nil {
+		sortChanges(changes.New)
+		sortChanges(changes.Ongoing)
+		sortChanges(changes.Resolved)
+	}
+	data.Changes = changes
+	return snapshot
+}
+
+// metricOrder 指标在报告中的展示顺序
+var metricOrder = map[string]int{
+	MetricReachability: 0,
+	MetricDisk:         1,
+	MetricForecast:     2,
+	MetricInode:        3,
+	MetricCPU:          4,
+	MetricMemory:       5,
+}
+
+// sortChanges 按主机名称和指标顺序排序，保证报告内容稳定
+func sortChanges(changes []Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Node != changes[j].Node {
+			return changes[i].Node < changes[j].Node
+		}
+		return metricOrder[changes[i].Metric] < metricOrder[changes[j].Metric]
+	})
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("History", func() {
+	var (
+		data     *ReportData
+		previous *Snapshot
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		data = sampleReportData()
+		now = data.Metadata.GeneratedAt
+		previous = &Snapshot{
+			GeneratedAt: now.Add(-24 * time.Hour),
+			Nodes: map[string]NodeSnapshot{
+				"192.168.0.1:9100": {Status: 1, Findings: map[string]time.Time{
+					MetricCPU: now.Add(-24 * time.Hour),
+				}},
+				"192.168.0.2:9100": {Status: 1, Findings: map[string]time.Time{
+					MetricDisk:  now.Add(-72 * time.Hour),
+					MetricInode: now.Add(-24 * time.Hour),
+				}},
+			},
+		}
+	})
+
+	It("should mark new, ongoing and resolved findings", func() {
+		snapshot := CompareHistory(data, previous)
+
+		Expect(data.Changes).NotTo(BeNil())
+		Expect(data.Changes.Previous).To(Equal(previous.GeneratedAt))
+		Expect(data.Changes.New).To(ConsistOf(
+			HaveField("Metric", MetricCPU),
+			HaveField("Metric", MetricMemory),
+		))
+		Expect(data.Changes.Ongoing).To(HaveLen(1))
+		Expect(data.Changes.Ongoing[0]).To(And(
+			HaveField("Node", "192.168.0.2:9100"),
+			HaveField("Metric", MetricDisk),
+			HaveField("Duration", 72*time.Hour),
+		))
+		Expect(data.Changes.Resolved).To(HaveLen(2))
+		Expect(data.Changes.Resolved[0]).To(HaveField("Node", "192.168.0.1:9100"))
+		Expect(data.Changes.Resolved[1]).To(HaveField("Metric", MetricInode))
+
+		// 持续的异常沿用首次出现的时间，新增的异常从本次开始计算
+		Expect(snapshot.GeneratedAt).To(Equal(now))
+		Expect(snapshot.Nodes["192.168.0.1:9100"].Findings).To(BeEmpty())
+		Expect(snapshot.Nodes["192.168.0.2:9100"].Findings).To(Equal(map[string]time.Time{
+			MetricDisk:   now.Add(-72 * time.Hour),
+			MetricCPU:    now,
+			MetricMemory: now,
+		}))
+		Expect(data.Inspection.Node[1].Changes).To(HaveLen(4))
+	})
+
+	It("should not compare on the first run", func() {
+		snapshot := CompareHistory(data, nil)
+		Expect(data.Changes).To(BeNil())
+		Expect(data.Inspection.Node[1].Changes).To(BeEmpty())
+		Expect(snapshot.Nodes["192.168.0.2:9100"].Findings).To(HaveLen(3))
+	})
+
+	It("should keep metric findings of unreachable nodes", func() {
+		data.Inspection.Node[1] = NewUnreachableNode("192.168.0.2:9100", NodeHealth{State: NodeStateDown})
+		snapshot := CompareHistory(data, previous)
+
+		Expect(data.Changes.New).To(ConsistOf(HaveField("Metric", MetricReachability)))
+		Expect(data.Changes.Resolved).To(ConsistOf(HaveField("Node", "192.168.0.1:9100")))
+		Expect(snapshot.Nodes["192.168.0.2:9100"].Findings).To(HaveKeyWithValue(MetricDisk, now.Add(-72*time.Hour)))
+		Expect(snapshot.Nodes["192.168.0.2:9100"].Findings).To(HaveKey(MetricReachability))
+	})
+
+	It("should render the changes in every format", func() {
+		CompareHistory(data, previous)
+		generator := NewGenerator()
+
+		html, err := generator.GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("与上次巡检对比"))
+		Expect(html).To(ContainSubstring("新增 2 项，持续 1 项，已恢复 2 项"))
+		Expect(html).To(ContainSubstring("硬盘使用率 持续3天"))
+		Expect(html).To(ContainSubstring("CPU使用率 已恢复"))
+
+		summary, err := generator.GenerateSummaryHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary).To(ContainSubstring("新增 2 项，持续 1 项，已恢复 2 项"))
+
+		text, err := generator.generateText(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(text)).To(ContainSubstring("变化: 硬盘使用率 持续3天、inode使用率 已恢复、CPU使用率 新增"))
+
+		markdown, err := generator.generateMarkdown(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(markdown)).To(ContainSubstring("- 192.168.0.2:9100: 内存使用率 新增"))
+
+		raw, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var report JSONReport
+		Expect(json.Unmarshal(raw, &report)).To(Succeed())
+		Expect(report.Changes).NotTo(BeNil())
+		Expect(report.Changes.PreviousRun).To(Equal("2025-02-28T10:00:00+08:00"))
+		Expect(report.Changes.Ongoing).To(ConsistOf(JSONChange{
+			Node:            "192.168.0.2:9100",
+			Metric:          MetricDisk,
+			Since:           "2025-02-26T10:00:00+08:00",
+			DurationSeconds: 72 * 3600,
+		}))
+	})
+
+	It("should say so when there is nothing to compare", func() {
+		data.Inspection.Node = data.Inspection.Node[:1]
+		data.Inspection.Node[0] = NodeMetric{Name: "192.168.0.1:9100"}
+		CompareHistory(data, &Snapshot{GeneratedAt: now.Add(-time.Hour)})
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("上次和本次巡检均没有异常。"))
+	})
+})
@@ -0,0 +1,521 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion JSON报告的schema版本。
+// 同一版本内只允许新增可选字段，重命名、删除字段或修改字段含义必须升级版本，
+// 字段说明见docs/report-json-schema.md。
+const SchemaVersion = "v1"
+
+// 状态取值
+const (
+	jsonStatusNormal   = "normal"
+	jsonStatusAbnormal = "abnormal"
+)
+
+// JSONReport JSON报告的顶层结构
+type JSONReport struct {
+	SchemaVersion string       `json:"schemaVersion"`
+	Metadata      JSONMetadata `json:"metadata"`
+	Summary       JSONSummary  `json:"summary"`
+	Nodes         []JSONNode   `json:"nodes"`
+	// 相对上次巡检的变化，没有历史记录时省略
+	Changes *JSONChanges `json:"changes,omitempty"`
+	// 已过期的静默规则，没有时省略
+	ExpiredSilences []JSONSilence `json:"expiredSilences,omitempty"`
+	// Prometheus返回的查询警告，没有时省略
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// JSONSilence 静默规则
+type JSONSilence struct {
+	Node      string            `json:"node,omitempty"`
+	Selector  map[string]string `json:"selector,omitempty"`
+	Metric    string            `json:"metric"`
+	Reason    string            `json:"reason"`
+	Owner     string            `json:"owner"`
+	ExpiresAt string            `json:"expiresAt"`
+}
+
+// JSONChanges 相对上次巡检的变化汇总
+type JSONChanges struct {
+	PreviousRun string       `json:"previousRun"`
+	New         []JSONChange `json:"new"`
+	Ongoing     []JSONChange `json:"ongoing"`
+	Resolved    []JSONChange `json:"resolved"`
+}
+
+// JSONChange 主机某项指标的异常变化
+type JSONChange struct {
+	Node   string `json:"node"`
+	Metric string `json:"metric"`
+	// 异常首次出现的时间
+	Since string `json:"since"`
+	// 异常截至本次巡检已持续的秒数
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// JSONMetadata 报告元数据
+type JSONMetadata struct {
+	Business         string `json:"business"`
+	Title            string `json:"title"`
+	GeneratedAt      string `json:"generatedAt"`
+	ComparisonOffset string `json:"comparisonOffset"`
+	Language         string `json:"language"`
+	// 额外的对比偏移，与nodes[].offsets一一对应，未配置时省略
+	Comparisons []JSONComparison `json:"comparisons,omitempty"`
+	// 按指标配置的差值规则，未配置时省略
+	DeltaRules []JSONDeltaRule `json:"deltaRules,omitempty"`
+	// 磁盘写满预测配置，未开启预测时省略
+	Forecast *JSONForecastConfig `json:"forecast,omitempty"`
+	// 动态基线配置，未开启基线时省略
+	Baseline *JSONBaselineConfig `json:"baseline,omitempty"`
+}
+
+// JSONBaselineConfig 动态基线配置
+type JSONBaselineConfig struct {
+	Days        int     `json:"days"`
+	Sigma       float64 `json:"sigma"`
+	StepSeconds float64 `json:"stepSeconds"`
+}
+
+// JSONBaseline 主机单项指标的动态基线
+type JSONBaseline struct {
+	Mean      float64 `json:"mean"`
+	Stddev    float64 `json:"stddev"`
+	Samples   int     `json:"samples"`
+	Lower     float64 `json:"lower"`
+	Upper     float64 `json:"upper"`
+	Deviation float64 `json:"deviation"`
+	Status    string  `json:"status"`
+}
+
+// JSONDeltaRule 单项指标的差值规则
+type JSONDeltaRule struct {
+	Metric    string `json:"metric"`
+	Mode      string `json:"mode"`
+	Direction string `json:"direction"`
+	// 差值阈值，absolute时单位为百分点，relative时单位为百分比
+	Threshold   float64 `json:"threshold"`
+	MinBaseline float64 `json:"minBaseline"`
+}
+
+// JSONComparison 对比偏移及其差值阈值
+type JSONComparison struct {
+	Offset        string  `json:"offset"`
+	RateThreshold float64 `json:"rateThreshold"`
+}
+
+// JSONOffset 主机与某个额外对比偏移相比的各项指标
+type JSONOffset struct {
+	Offset string `json:"offset"`
+	// 对比值查询失败时为false，此时各项指标省略
+	Valid  bool             `json:"valid"`
+	Disk   *JSONOffsetValue `json:"disk,omitempty"`
+	Inode  *JSONOffsetValue `json:"inode,omitempty"`
+	CPU    *JSONOffsetValue `json:"cpu,omitempty"`
+	Memory *JSONOffsetValue `json:"memory,omitempty"`
+}
+
+// JSONOffsetValue 单项指标的对比值和差值，数值均为百分比
+type JSONOffsetValue struct {
+	Previous    float64 `json:"previous"`
+	Delta       float64 `json:"delta"`
+	DeltaStatus string  `json:"deltaStatus"`
+}
+
+// JSONForecastConfig 磁盘写满预测配置
+type JSONForecastConfig struct {
+	Window        string  `json:"window"`
+	ThresholdDays float64 `json:"thresholdDays"`
+}
+
+// JSONSummary 巡检结果汇总
+type JSONSummary struct {
+	Total int `json:"total"`
+	// 指标异常的可达主机数，包含异常已全部被确认的主机，不包含不可达的主机
+	Abnormal    int `json:"abnormal"`
+	Unreachable int `json:"unreachable"`
+	// 异常已全部被确认的主机数
+	Acknowledged int `json:"acknowledged"`
+}
+
+// JSONNode 单个主机的巡检结果
+type JSONNode struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// 异常是否已全部被静默规则确认，此时status仍为abnormal
+	FullyAcknowledged bool `json:"fullyAcknowledged,omitempty"`
+	// 采集状态，不为ok时metrics中的各项指标为null
+	State string `json:"state"`
+	// 不可达或采集失败的原因
+	Reason string `json:"reason,omitempty"`
+	// 最近一次抓取距今的秒数
+	ScrapeAgeSeconds float64 `json:"scrapeAgeSeconds,omitempty"`
+	// 提供该主机数据的数据源，只有一个数据源时省略
+	DataSource string      `json:"dataSource,omitempty"`
+	Metrics    JSONMetrics `json:"metrics"`
+	// 与额外对比偏移相比的结果，未配置额外对比偏移时省略
+	Offsets []JSONOffset `json:"offsets,omitempty"`
+	// 磁盘写满预测，磁盘没有增长趋势时省略
+	Forecast *JSONForecast `json:"forecast,omitempty"`
+	// 各分区明细，没有分区数据时省略
+	Filesystems []JSONFilesystem `json:"filesystems,omitempty"`
+	// 生效的阈值覆盖，未使用覆盖规则时省略
+	Thresholds *JSONThresholds `json:"thresholds,omitempty"`
+	// 被静默规则确认的异常，没有时省略
+	Acknowledged []JSONAcknowledgement `json:"acknowledged,omitempty"`
+	// 各项指标的动态基线，key为指标名称，未开启基线或历史数据不足时省略
+	Baseline map[string]JSONBaseline `json:"baseline,omitempty"`
+}
+
+// JSONAcknowledgement 被静默规则确认的异常
+type JSONAcknowledgement struct {
+	Metric    string `json:"metric"`
+	Reason    string `json:"reason"`
+	Owner     string `json:"owner"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// JSONThresholds 主机生效的使用率阈值（百分比）及其来源
+type JSONThresholds struct {
+	Disk   float64 `json:"disk"`
+	Inode  float64 `json:"inode"`
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+	// 生效的覆盖规则，按具体程度从高到低排列
+	Sources []string `json:"sources"`
+}
+
+// JSONFilesystem 单个分区的硬盘和inode使用率
+type JSONFilesystem struct {
+	Device     string               `json:"device"`
+	Mountpoint string               `json:"mountpoint"`
+	FSType     string               `json:"fstype"`
+	Status     string               `json:"status"`
+	Disk       JSONMetric           `json:"disk"`
+	Inode      JSONMetric           `json:"inode"`
+	Thresholds JSONFilesystemLimits `json:"thresholds"`
+}
+
+// JSONFilesystemLimits 分区生效的使用率阈值（百分比）
+type JSONFilesystemLimits struct {
+	Disk  float64 `json:"disk"`
+	Inode float64 `json:"inode"`
+}
+
+// JSONForecast 磁盘写满预测结果
+type JSONForecast struct {
+	Mountpoint    string  `json:"mountpoint"`
+	DaysUntilFull float64 `json:"daysUntilFull"`
+	Status        string  `json:"status"`
+}
+
+// JSONMetrics 主机的各项指标，主机不可达时均为null
+type JSONMetrics struct {
+	Disk   *JSONMetric `json:"disk"`
+	Inode  *JSONMetric `json:"inode"`
+	CPU    *JSONMetric `json:"cpu"`
+	Memory *JSONMetric `json:"memory"`
+}
+
+// JSONMetric 单项指标，数值均为百分比
+type JSONMetric struct {
+	Current       float64 `json:"current"`
+	Previous      float64 `json:"previous"`
+	Delta         float64 `json:"delta"`
+	CurrentStatus string  `json:"currentStatus"`
+	DeltaStatus   string  `json:"deltaStatus"`
+}
+
+// jsonStatus 将状态转换为JSON中的取值，异常被确认后仍为abnormal，v1的状态只有normal和abnormal
+func jsonStatus(status int) string {
+	if status == 1 || status == StatusAcknowledged {
+		return jsonStatusAbnormal
+	}
+	return jsonStatusNormal
+}
+
+// jsonMetric 构造单项指标
+func jsonMetric(now, offset, rate float64, nowStatus, rateStatus int) JSONMetric {
+	return JSONMetric{
+		Current:       now,
+		Previous:      offset,
+		Delta:         rate,
+		CurrentStatus: jsonStatus(nowStatus),
+		DeltaStatus:   jsonStatus(rateStatus),
+	}
+}
+
+// jsonOffsets 构造与额外对比偏移相比的结果
+func jsonOffsets(offsets []OffsetMetric) []JSONOffset {
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	value := func(previous, delta float64, status int) *JSONOffsetValue {
+		return &JSONOffsetValue{Previous: previous, Delta: delta, DeltaStatus: jsonStatus(status)}
+	}
+	result := make([]JSONOffset, 0, len(offsets))
+	for _, offset := range offsets {
+		item := JSONOffset{Offset: offset.Offset, Valid: offset.Valid}
+		if offset.Valid {
+			item.Disk = value(offset.DiskOffset, offset.DiskRate, offset.DiskRateStatus)
+			item.Inode = value(offset.InodeOffset, offset.InodeRate, offset.InodeRateStatus)
+			item.CPU = value(offset.CPUOffset, offset.CPURate, offset.CPURateStatus)
+			item.Memory = value(offset.MemOffset, offset.MemRate, offset.MemRateStatus)
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// jsonChanges 构造异常变化列表，没有变化时返回空数组
+func jsonChanges(changes []Change) []JSONChange {
+	result := make([]JSONChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, JSONChange{
+			Node:            change.Node,
+			Metric:          change.Metric,
+			Since:           change.Since.Format(time.RFC3339),
+			DurationSeconds: change.Duration.Seconds(),
+		})
+	}
+	return result
+}
+
+// jsonFilesystems 构造分区明细
+func jsonFilesystems(filesystems []FilesystemMetric) []JSONFilesystem {
+	if len(filesystems) == 0 {
+		return nil
+	}
+
+	result := make([]JSONFilesystem, 0, len(filesystems))
+	for _, fs := range filesystems {
+		result = append(result, JSONFilesystem{
+			Device:     fs.Device,
+			Mountpoint: fs.Mountpoint,
+			FSType:     fs.FSType,
+			Status:     jsonStatus(fs.Status),
+			Disk:       jsonMetric(fs.DiskNow, fs.DiskOffset, fs.DiskRate, fs.DiskNowStatus, fs.DiskRateStatus),
+			Inode:      jsonMetric(fs.InodeNow, fs.InodeOffset, fs.InodeRate, fs.InodeNowStatus, fs.InodeRateStatus),
+			Thresholds: JSONFilesystemLimits{Disk: fs.DiskThreshold, Inode: fs.InodeThreshold},
+		})
+	}
+	return result
+}
+
+// NewJSONReport 将报告数据转换为JSON报告结构
+func NewJSONReport(data *ReportData) *JSONReport {
+	result := &JSONReport{
+		SchemaVersion: SchemaVersion,
+		Metadata: JSONMetadata{
+			Business:         data.Metadata.Business,
+			Title:            data.Metadata.Title,
+			GeneratedAt:      data.Metadata.GeneratedAt.Format(time.RFC3339),
+			ComparisonOffset: data.ComparisonOffset(),
+			Language:         string(data.Catalog().Language),
+		},
+		Summary: JSONSummary{
+			Total:        len(data.Inspection.Node),
+			Abnormal:     jsonAbnormalCount(data.Inspection.Node),
+			Unreachable:  len(data.UnreachableNodes()),
+			Acknowledged: len(data.AcknowledgedNodes()),
+		},
+		Nodes: make([]JSONNode, 0, len(data.Inspection.Node)),
+	}
+
+	if forecast := data.Inspection.Forecast; forecast != nil {
+		result.Metadata.Forecast = &JSONForecastConfig{
+			Window:        forecast.Window,
+			ThresholdDays: forecast.ThresholdDays,
+		}
+	}
+
+	sigma := 0.0
+	if baseline := data.Inspection.Baseline; baseline != nil {
+		sigma = baseline.Sigma
+		result.Metadata.Baseline = &JSONBaselineConfig{
+			Days:        baseline.Days,
+			Sigma:       baseline.Sigma,
+			StepSeconds: baseline.Step.Seconds(),
+		}
+	}
+
+	for _, comparison := range data.ExtraComparisons() {
+		result.Metadata.Comparisons = append(result.Metadata.Comparisons, JSONComparison{
+			Offset:        comparison.Offset,
+			RateThreshold: comparison.RateThreshold,
+		})
+	}
+
+	for _, rule := range data.Inspection.Thresholds.DeltaRules() {
+		item := JSONDeltaRule{
+			Metric:      rule.Metric,
+			Mode:        rule.Mode,
+			Direction:   rule.Direction,
+			Threshold:   rule.Threshold,
+			MinBaseline: rule.MinBaseline,
+		}
+		if item.Mode == "" {
+			item.Mode = DeltaAbsolute
+		}
+		if item.Direction == "" {
+			item.Direction = DirectionUp
+		}
+		if item.Threshold <= 0 {
+			item.Threshold = data.RateThreshold()
+		}
+		result.Metadata.DeltaRules = append(result.Metadata.DeltaRules, item)
+	}
+
+	if changes := data.Changes; changes != nil {
+		result.Changes = &JSONChanges{
+			PreviousRun: changes.Previous.Format(time.RFC3339),
+			New:         jsonChanges(changes.New),
+			Ongoing:     jsonChanges(changes.Ongoing),
+			Resolved:    jsonChanges(changes.Resolved),
+		}
+	}
+
+	for _, silence := range data.ExpiredSilences {
+		result.ExpiredSilences = append(result.ExpiredSilences, JSONSilence{
+			Node:      silence.Node,
+			Selector:  silence.Selector,
+			Metric:    silence.Metric,
+			Reason:    silence.Reason,
+			Owner:     silence.Owner,
+			ExpiresAt: silence.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+	result.Warnings = data.QueryWarnings
+
+	for _, node := range data.Inspection.Node {
+		var forecast *JSONForecast
+		if node.DiskForecast != nil {
+			forecast = &JSONForecast{
+				Mountpoint:    node.DiskForecast.Mountpoint,
+				DaysUntilFull: node.DiskForecast.Days,
+				Status:        jsonStatus(node.DiskFullStatus),
+			}
+		}
+
+		state := node.Health.State
+		if state == "" {
+			state = NodeStateOK
+		}
+
+		result.Nodes = append(result.Nodes, JSONNode{
+			Name:              node.Name,
+			Status:            jsonStatus(node.Status),
+			FullyAcknowledged: node.Status == StatusAcknowledged,
+			State:             state,
+			Reason:            node.Health.Reason,
+			ScrapeAgeSeconds:  node.Health.ScrapeAge.Seconds(),
+			DataSource:        node.DataSource,
+			Metrics:           jsonNodeMetrics(node),
+			Offsets:           jsonOffsets(node.Offsets),
+			Forecast:          forecast,
+			Filesystems:       jsonFilesystems(node.Filesystems),
+			Thresholds:        jsonThresholds(node.Thresholds),
+			Acknowledged:      jsonAcknowledgements(node.Acknowledged),
+			Baseline:          jsonBaselines(node, sigma),
+		})
+	}
+
+	return result
+}
+
+// jsonAbnormalCount 统计指标异常的可达主机数，与v1的summary.abnormal含义一致
+func jsonAbnormalCount(nodes []NodeMetric) int {
+	count := 0
+	for _, node := range nodes {
+		if node.Reachable() && node.Status != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// jsonNodeMetrics 构造主机的各项指标，主机不可达时没有有效数值，各项指标均为null
+func jsonNodeMetrics(node NodeMetric) JSONMetrics {
+	if !node.Reachable() {
+		return JSONMetrics{}
+	}
+
+	metric := func(now, offset, rate float64, nowStatus, rateStatus int) *JSONMetric {
+		m := jsonMetric(now, offset, rate, nowStatus, rateStatus)
+		return &m
+	}
+	return JSONMetrics{
+		Disk:   metric(node.DiskNow, node.DiskOffset, node.DiskRate, node.DiskNowStatus, node.DiskRateStatus),
+		Inode:  metric(node.InodeNow, node.InodeOffset, node.InodeRate, node.InodeNowStatus, node.InodeRateStatus),
+		CPU:    metric(node.CPUNow, node.CPUOffset, node.CPURate, node.CPUNowStatus, node.CPURateStatus),
+		Memory: metric(node.MemNow, node.MemOffset, node.MemRate, node.MemNowStatus, node.MemRateStatus),
+	}
+}
+
+// jsonBaselines 转换主机的动态基线，没有足够历史数据时返回nil
+func jsonBaselines(node NodeMetric, sigma float64) map[string]JSONBaseline {
+	rows := node.BaselineRows()
+	if len(rows) == 0 {
+		return nil
+	}
+	result := make(map[string]JSONBaseline, len(rows))
+	for _, row := range rows {
+		result[row.Metric] = JSONBaseline{
+			Mean:      row.Mean,
+			Stddev:    row.Stddev,
+			Samples:   row.Samples,
+			Lower:     row.Lower(sigma),
+			Upper:     row.Upper(sigma),
+			Deviation: row.Deviation,
+			Status:    jsonStatus(row.Status),
+		}
+	}
+	return result
+}
+
+// jsonAcknowledgements 转换主机被确认的异常，没有时返回nil
+func jsonAcknowledgements(acks []Acknowledgement) []JSONAcknowledgement {
+	if len(acks) == 0 {
+		return nil
+	}
+	result := make([]JSONAcknowledgement, 0, len(acks))
+	for _, ack := range acks {
+		result = append(result, JSONAcknowledgement{
+			Metric:    ack.Metric,
+			Reason:    ack.Silence.Reason,
+			Owner:     ack.Silence.Owner,
+			ExpiresAt: ack.Silence.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+	return result
+}
+
+// jsonThresholds 转换主机生效的阈值覆盖，未使用覆盖规则时返回nil
+func jsonThresholds(thresholds Thresholds) *JSONThresholds {
+	if len(thresholds.Sources) == 0 {
+		return nil
+	}
+	return &JSONThresholds{
+		Disk:    thresholds.usage(MetricDisk),
+		Inode:   thresholds.usage(MetricInode),
+		CPU:     thresholds.usage(MetricCPU),
+		Memory:  thresholds.usage(MetricMemory),
+		Sources: thresholds.Sources,
+	}
+}
+
+// GenerateJSON 生成JSON报告
+func GenerateJSON(data *ReportData) ([]byte, error) {
+	result, err := json.MarshalIndent(NewJSONReport(data), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成JSON报告失败: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeGroup 主机分组，按主机列表或Prometheus目标标签确定成员
+type NodeGroup struct {
+	Name   string
+	Nodes  []string
+	Labels map[string]string
+}
+
+// Contains 主机是否属于该分组
+func (g NodeGroup) Contains(name string, labels map[string]string) bool {
+	for _, node := range g.Nodes {
+		if node == name {
+			return true
+		}
+	}
+	return len(g.Labels) > 0 && matchLabels(g.Labels, labels)
+}
+
+// ThresholdOverride 按主机、标签选择器或分组覆盖阈值，Node、Selector和Group只能设置一个。
+// 使用率阈值为0表示不覆盖。
+type ThresholdOverride struct {
+	Node     string
+	Selector map[string]string
+	Group    string
+	// 使用率阈值（百分比）
+	Disk   float64
+	Inode  float64
+	CPU    float64
+	Memory float64
+	// 按指标覆盖的差值规则
+	Deltas []DeltaRule
+}
+
+// Validate 检查匹配条件是否有且只有一个
+func (o ThresholdOverride) Validate() error {
+	count := 0
+	for _, set := range []bool{o.Node != "", len(o.Selector) > 0, o.Group != ""} {
+		if set {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("阈值覆盖%s必须且只能指定node、selector、group中的一个", o.Source())
+	}
+	return nil
+}
+
+// Source 返回覆盖规则的描述，如 node:10.0.0.1:9100、selector:{env="prod"}、group:jvm
+func (o ThresholdOverride) Source() string {
+	switch {
+	case o.Node != "":
+		return "node:" + o.Node
+	case len(o.Selector) > 0:
+		return "selector:" + formatSelector(o.Selector)
+	case o.Group != "":
+		return "group:" + o.Group
+	}
+	return "{}"
+}
+
+// specificity 返回规则的具体程度，主机最具体，其次是标签选择器（标签越多越具体），最后是分组
+func (o ThresholdOverride) specificity() int {
+	switch {
+	case o.Node != "":
+		return 1 << 20
+	case len(o.Selector) > 0:
+		return 1<<10 + len(o.Selector)
+	}
+	return 0
+}
+
+// matches 主机是否匹配该规则
+func (o ThresholdOverride) matches(name string, labels map[string]string, groups map[string]NodeGroup) bool {
+	switch {
+	case o.Node != "":
+		return o.Node == name
+	case len(o.Selector) > 0:
+		return matchLabels(o.Selector, labels)
+	case o.Group != "":
+		group, ok := groups[o.Group]
+		return ok && group.Contains(name, labels)
+	}
+	return false
+}
+
+// formatSelector 按标签名排序格式化标签选择器，如 {env="prod",role="db"}
+func formatSelector(selector map[string]string) string {
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", key, selector[key]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// matchLabels labels是否包含selector中的全部标签
+func matchLabels(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveThresholds 返回主机生效的阈值规则。
+// 匹配的覆盖规则按主机、标签选择器、分组的顺序（同级按配置顺序）逐项合并，
+// 每项阈值使用最具体的规则中配置的值，都未配置时使用defaults中的值；
+// 至少有一项阈值生效的规则记录在Sources中。
+func ResolveThresholds(
+	defaults Thresholds,
+	name string,
+	labels map[string]string,
+	groups []NodeGroup,
+	overrides []ThresholdOverride,
+) Thresholds {
+	groupIndex := make(map[string]NodeGroup, len(groups))
+	for _, group := range groups {
+		groupIndex[group.Name] = group
+	}
+
+	var matched []ThresholdOverride
+	for _, override := range overrides {
+		if override.matches(name, labels, groupIndex) {
+			matched = append(matched, override)
+		}
+	}
+	if len(matched) == 0 {
+		return defaults
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].specificity() > matched[j].specificity()
+	})
+
+	resolved := Thresholds{Deltas: make(map[string]DeltaRule, len(defaults.Deltas))}
+	// 从最具体的规则开始，每项阈值只取第一条配置了该项的规则
+	overridden := make(map[string]bool)
+	for _, override := range matched {
+		applied := false
+		for _, field := range []struct {
+			name   string
+			value  float64
+			target *float64
+		}{
+			{MetricDisk, override.Disk, &resolved.Disk},
+			{MetricInode, override.Inode, &resolved.Inode},
+			{MetricCPU, override.CPU, &resolved.CPU},
+			{MetricMemory, override.Memory, &resolved.Memory},
+		} {
+			if field.value > 0 && !overridden[field.name] {
+				*field.target = field.value
+				overridden[field.name] = true
+				applied = true
+			}
+		}
+		for _, rule := range override.Deltas {
+			if _, ok := resolved.Deltas[rule.Metric]; !ok {
+				resolved.Deltas[rule.Metric] = rule
+				applied = true
+			}
+		}
+		if applied {
+			resolved.Sources = append(resolved.Sources, override.Source())
+		}
+	}
+
+	// 未被覆盖的阈值使用默认值
+	for name, target := range map[string]*float64{
+		MetricDisk:   &resolved.Disk,
+		MetricInode:  &resolved.Inode,
+		MetricCPU:    &resolved.CPU,
+		MetricMemory: &resolved.Memory,
+	} {
+		if !overridden[name] {
+			*target = defaults.usage(name)
+		}
+	}
+	for metric, rule := range defaults.Deltas {
+		if _, ok := resolved.Deltas[metric]; !ok {
+			resolved.Deltas[metric] = rule
+		}
+	}
+	if len(resolved.Deltas) == 0 {
+		resolved.Deltas = nil
+	}
+	return resolved
+}
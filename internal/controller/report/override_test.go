@@ -0,0 +1,116 @@
+package report
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Threshold overrides", func() {
+	groups := []NodeGroup{
+		{Name: "db", Nodes: []string{"10.0.0.1:9100"}},
+		{Name: "prod", Labels: map[string]string{"env": "prod"}},
+	}
+	labels := map[string]string{"instance": "10.0.0.1:9100", "env": "prod", "role": "mysql"}
+
+	It("should keep the defaults when no override matches", func() {
+		defaults := NewThresholds([]DeltaRule{{Metric: MetricCPU, Threshold: 20}})
+		resolved := ResolveThresholds(defaults, "10.0.0.9:9100", nil, groups, []ThresholdOverride{
+			{Node: "10.0.0.1:9100", CPU: 90},
+			{Group: "prod", CPU: 70},
+		})
+		Expect(resolved).To(Equal(defaults))
+	})
+
+	It("should resolve each threshold from the most specific override", func() {
+		resolved := ResolveThresholds(Thresholds{}, "10.0.0.1:9100", labels, groups, []ThresholdOverride{
+			{Group: "db", CPU: 70, Memory: 85, Disk: 70},
+			{Selector: map[string]string{"env": "prod"}, CPU: 75},
+			{Selector: map[string]string{"env": "prod", "role": "mysql"}, CPU: 80},
+			{Node: "10.0.0.1:9100", Disk: 95},
+			{Group: "prod", Inode: 70},
+		})
+
+		Expect(resolved.Disk).To(Equal(95.0))
+		Expect(resolved.CPU).To(Equal(80.0))
+		Expect(resolved.Memory).To(Equal(85.0))
+		Expect(resolved.Inode).To(Equal(70.0))
+		Expect(resolved.Sources).To(Equal([]string{
+			"node:10.0.0.1:9100",
+			`selector:{env="prod",role="mysql"}`,
+			"group:db",
+			"group:prod",
+		}))
+	})
+
+	It("should merge delta rules per metric", func() {
+		defaults := NewThresholds([]DeltaRule{
+			{Metric: MetricCPU, Threshold: 20},
+			{Metric: MetricMemory, Direction: DirectionDown, Threshold: 30},
+		})
+		resolved := ResolveThresholds(defaults, "10.0.0.1:9100", labels, groups, []ThresholdOverride{
+			{Group: "db", Deltas: []DeltaRule{{Metric: MetricCPU, Threshold: 50}}},
+		})
+
+		Expect(resolved.delta(MetricCPU).Threshold).To(Equal(50.0))
+		Expect(resolved.delta(MetricMemory).Direction).To(Equal(DirectionDown))
+		Expect(defaults.delta(MetricCPU).Threshold).To(Equal(20.0))
+	})
+
+	It("should require exactly one matcher", func() {
+		Expect(ThresholdOverride{Node: "a"}.Validate()).To(Succeed())
+		Expect(ThresholdOverride{CPU: 90}.Validate()).NotTo(Succeed())
+		Expect(ThresholdOverride{Node: "a", Group: "db"}.Validate()).NotTo(Succeed())
+	})
+
+	It("should check usage and filesystems against the resolved thresholds", func() {
+		node := NodeMetric{
+			Name:   "10.0.0.1:9100",
+			CPUNow: 75, MemNow: 85,
+			Filesystems: []FilesystemMetric{
+				{Mountpoint: "/", DiskNow: 85},
+				{Mountpoint: "/data", DiskNow: 97},
+			},
+			Thresholds: ResolveThresholds(Thresholds{}, "10.0.0.1:9100", labels, groups, []ThresholdOverride{
+				{Node: "10.0.0.1:9100", Disk: 90, CPU: 80, Memory: 90},
+			}),
+		}
+		Expect(ApplyFilesystemThresholds(node.Filesystems, []FilesystemThreshold{{Mountpoint: "/data", Disk: 95}})).To(Succeed())
+		CheckThresholds(&node)
+
+		Expect(node.CPUNowStatus).To(Equal(0))
+		Expect(node.MemNowStatus).To(Equal(0))
+		Expect(node.Filesystems[0].DiskThreshold).To(Equal(90.0))
+		Expect(node.Filesystems[0].Status).To(Equal(0))
+		Expect(node.Filesystems[1].DiskThreshold).To(Equal(95.0))
+		Expect(node.Filesystems[1].Status).To(Equal(1))
+		Expect(node.AbnormalItems()).To(Equal([]string{MetricDisk}))
+	})
+
+	It("should show the applied override in the report", func() {
+		data := sampleReportData()
+		node := &data.Inspection.Node[0]
+		node.Thresholds = ResolveThresholds(Thresholds{}, node.Name, nil, groups, []ThresholdOverride{
+			{Node: node.Name, CPU: 90},
+		})
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("阈值覆盖 node:" + node.Name + ": 硬盘 80%、inode 60%、CPU 90%、内存 80%"))
+
+		text, err := NewGenerator().generateText(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(text)).To(ContainSubstring("阈值覆盖 node:" + node.Name))
+
+		raw, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var report JSONReport
+		Expect(json.Unmarshal(raw, &report)).To(Succeed())
+		Expect(report.Nodes[0].Thresholds).To(Equal(&JSONThresholds{
+			Disk: 80, Inode: 60, CPU: 90, Memory: 80,
+			Sources: []string{"node:" + node.Name},
+		}))
+		Expect(report.Nodes[1].Thresholds).To(BeNil())
+	})
+})
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Format 报告输出格式
+type Format string
+
+// 内置的报告输出格式
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatXLSX     Format = "xlsx"
+)
+
+// Renderer 报告渲染器，将报告数据渲染为某种输出格式
+type Renderer interface {
+	// ContentType 渲染结果的MIME类型
+	ContentType() string
+	// Extension 作为文件保存时的扩展名，不含点
+	Extension() string
+	// Render 渲染报告
+	Render(data *ReportData) ([]byte, error)
+}
+
+// rendererFunc 使用函数实现的渲染器
+type rendererFunc struct {
+	contentType string
+	extension   string
+	render      func(data *ReportData) ([]byte, error)
+}
+
+func (r rendererFunc) ContentType() string { return r.contentType }
+
+func (r rendererFunc) Extension() string { return r.extension }
+
+func (r rendererFunc) Render(data *ReportData) ([]byte, error) { return r.render(data) }
+
+// registerBuiltinRenderers 注册内置的渲染器
+func (g *Generator) registerBuiltinRenderers() {
+	g.Register(FormatHTML, rendererFunc{
+		contentType: "text/html; charset=UTF-8",
+		extension:   "html",
+		render: func(data *ReportData) ([]byte, error) {
+			html, err := g.GenerateHTML(data)
+			return []byte(html), err
+		},
+	})
+	g.Register(FormatMarkdown, rendererFunc{
+		contentType: "text/markdown; charset=UTF-8",
+		extension:   "md",
+		render:      g.generateMarkdown,
+	})
+	g.Register(FormatText, rendererFunc{
+		contentType: "text/plain; charset=UTF-8",
+		extension:   "txt",
+		render:      g.generateText,
+	})
+	g.Register(FormatJSON, rendererFunc{
+		contentType: "application/json",
+		extension:   "json",
+		render:      GenerateJSON,
+	})
+	g.Register(FormatCSV, rendererFunc{
+		contentType: "text/csv; charset=UTF-8",
+		extension:   "csv",
+		render:      GenerateCSV,
+	})
+	g.Register(FormatXLSX, rendererFunc{
+		contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		extension:   "xlsx",
+		render:      GenerateXLSX,
+	})
+}
+
+// Register 注册或替换某种格式的渲染器
+func (g *Generator) Register(format Format, renderer Renderer) {
+	g.renderers[format] = renderer
+}
+
+// Renderer 返回某种格式的渲染器
+func (g *Generator) Renderer(format Format) (Renderer, error) {
+	renderer, ok := g.renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("不支持的报告格式: %s", format)
+	}
+	return renderer, nil
+}
+
+// Formats 返回已注册的全部报告格式
+func (g *Generator) Formats() []Format {
+	formats := make([]Format, 0, len(g.renderers))
+	for format := range g.renderers {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+// Render 使用指定格式渲染报告
+func (g *Generator) Render(format Format, data *ReportData) ([]byte, error) {
+	renderer, err := g.Renderer(format)
+	if err != nil {
+		return nil, err
+	}
+	return renderer.Render(data)
+}
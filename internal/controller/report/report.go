@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"html/template"
 	"time"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
 )
 
 // NodeMetric 表示主机指标
 type NodeMetric struct {
 	Name string
-	// 硬盘使用率
+	// 抓取状态，不可达的主机只有Name、Health和Status有效
+	Health NodeHealth
+	// 硬盘使用率，Offset和Rate按主对比偏移计算
 	DiskNow        float64
 	DiskOffset     float64
 	DiskRate       float64
@@ -34,45 +38,119 @@ type NodeMetric struct {
 	MemRate       float64
 	MemNowStatus  int
 	MemRateStatus int
-	// 整体状态
+	// 与额外对比偏移相比的结果，与ReportData.ExtraComparisons一一对应
+	Offsets []OffsetMetric
+	// 各分区明细，硬盘和inode的汇总值取使用率最大的分区
+	Filesystems []FilesystemMetric
+	// 磁盘写满预测，未开启预测或磁盘没有增长趋势时为nil
+	DiskForecast   *DiskForecast
+	DiskFullStatus int
+	// Prometheus目标标签，用于匹配阈值覆盖规则
+	Labels map[string]string
+	// 提供该主机数据的数据源名称，只有一个数据源时为空
+	DataSource string
+	// 生效的阈值规则，需在CheckThresholds之前设置
+	Thresholds Thresholds
+	// 整体状态，1为异常，StatusAcknowledged为异常均已被静默规则确认
 	Status int
+	// 被静默规则确认的异常
+	Acknowledged []Acknowledgement
+	// 各项指标的动态基线，key为指标名称，未开启基线时为空
+	Baselines map[string]Baseline
+	// 最近24小时的使用率趋势，key为指标名称，未开启趋势图时为空
+	Trends map[string][]Point
+	// 各项异常相对上次巡检的变化，没有历史记录时为空
+	Changes []Change
 }
 
 // InspectionData 巡检数据
 type InspectionData struct {
 	Node []NodeMetric
+	// 磁盘写满预测配置，未开启预测时为nil
+	Forecast *ForecastConfig
+	// 对比偏移，第一个为主对比偏移，为空时只与24小时前对比
+	Comparisons []Comparison
+	// 默认的阈值规则，用于在报告中说明
+	Thresholds Thresholds
+	// 动态基线配置，未开启基线时为nil
+	Baseline *BaselineConfig
 }
 
 // ReportMetadata 报告元数据
 type ReportMetadata struct {
-	Business  string
-	Title     string
-	Date      string
-	Timestamp string
+	Business    string
+	Title       string
+	Date        string
+	Timestamp   string
+	GeneratedAt time.Time
+	Language    string
 }
 
 // ReportData 报告数据
 type ReportData struct {
 	Metadata   ReportMetadata
 	Inspection InspectionData
+	// 相对上次巡检的变化，没有历史记录时为nil
+	Changes *ChangeSet
+	// 已过期的静默规则
+	ExpiredSilences []Silence
+	// Prometheus返回的查询警告，已去重
+	QueryWarnings []string
 }
 
 // Generator 报告生成器
 type Generator struct {
-	// 不再需要TemplatePath字段
+	// 生成报告时内联到元素上的样式表
+	styles map[string]string
+	// 自定义报告模板，为空时使用内置模板
+	template *template.Template
+	// 按格式注册的渲染器
+	renderers map[Format]Renderer
+}
+
+// GeneratorOption 报告生成器选项
+type GeneratorOption func(*Generator)
+
+// WithTemplate 使用经ParseTemplate解析的自定义报告模板
+func WithTemplate(tmpl *template.Template) GeneratorOption {
+	return func(g *Generator) {
+		g.template = tmpl
+	}
 }
 
 // NewGenerator 创建新的报告生成器
-func NewGenerator() *Generator {
-	return &Generator{}
+func NewGenerator(opts ...GeneratorOption) *Generator {
+	g := &Generator{
+		styles:    defaultStyles,
+		renderers: make(map[Format]Renderer),
+	}
+	g.registerBuiltinRenderers()
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // GenerateHTML 生成HTML报告
 func (g *Generator) GenerateHTML(data *ReportData) (string, error) {
-	// 使用内置的模板变量中读取
-	tmpl, err := template.New("report").Parse(ReportTemplate)
-	if err != nil {
-		return "", fmt.Errorf("解析模板失败: %w", err)
+	funcs := g.funcMap(data.Catalog())
+
+	var tmpl *template.Template
+	if g.template != nil {
+		// 自定义模板在缓存中共享，复制后再绑定本次报告语言对应的模板函数
+		var err error
+		tmpl, err = g.template.Clone()
+		if err != nil {
+			return "", fmt.Errorf("复制模板失败: %w", err)
+		}
+		tmpl.Funcs(funcs)
+	} else {
+		// 使用内置的模板变量中读取
+		var err error
+		tmpl, err = template.New("report").Funcs(funcs).Parse(ReportTemplate)
+		if err != nil {
+			return "", fmt.Errorf("解析模板失败: %w", err)
+		}
 	}
 
 	var buf bytes.Buffer
@@ -83,71 +161,170 @@ func (g *Generator) GenerateHTML(data *ReportData) (string, error) {
 	return buf.String(), nil
 }
 
-// GenerateReport 根据指标数据生成报告
-func GenerateReport(business string, nodes []NodeMetric) (*ReportData, error) {
-	now := time.Now()
-	data := &ReportData{
-		Metadata: ReportMetadata{
-			Business:  business,
-			Title:     fmt.Sprintf("%s业务系统巡检报告", business),
-			Date:      now.Format("2006-01-02"),
-			Timestamp: now.Format("2006-01-02 15:04:05"),
-		},
-		Inspection: InspectionData{},
+// GenerateSummaryHTML 生成HTML摘要，用于完整报告以附件发送时的邮件正文
+func (g *Generator) GenerateSummaryHTML(data *ReportData) (string, error) {
+	tmpl, err := template.New("summary").Funcs(g.funcMap(data.Catalog())).Parse(SummaryTemplate)
+	if err != nil {
+		return "", fmt.Errorf("解析摘要模板失败: %w", err)
 	}
 
-	data.Inspection.Node = nodes
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("执行摘要模板失败: %w", err)
+	}
 
-	return data, nil
+	return buf.String(), nil
 }
 
-// CheckThresholds 检查阈值并设置状态
-func CheckThresholds(node *NodeMetric) {
-	// 检查硬盘使用率
-	if node.DiskNow > 80 {
-		node.DiskNowStatus = 1
-		node.Status = 1
+// Catalog 返回报告语言对应的消息目录
+func (d *ReportData) Catalog() *i18n.Catalog {
+	return i18n.NewCatalog(d.Metadata.Language)
+}
+
+// AbnormalNodes 返回状态异常的主机
+func (d *ReportData) AbnormalNodes() []NodeMetric {
+	var nodes []NodeMetric
+	for _, node := range d.Inspection.Node {
+		if node.Status == 1 {
+			nodes = append(nodes, node)
+		}
 	}
+	return nodes
+}
 
-	// 检查硬盘使用率波动
-	if node.DiskRate > 10 {
-		node.DiskRateStatus = 1
-		node.Status = 1
+// 指标名称，对应消息目录中的metric.<name>
+const (
+	MetricDisk   = "disk"
+	MetricInode  = "inode"
+	MetricCPU    = "cpu"
+	MetricMemory = "memory"
+)
+
+// AbnormalItems 返回主机的异常指标名称
+func (n NodeMetric) AbnormalItems() []string {
+	if !n.Reachable() {
+		return []string{MetricReachability}
 	}
 
-	// 检查inode使用率
-	if node.InodeNow > 60 {
-		node.InodeNowStatus = 1
-		node.Status = 1
+	var items []string
+	if n.DiskNowStatus == 1 || n.DiskRateStatus == 1 || n.offsetAbnormal(func(o OffsetMetric) int { return o.DiskRateStatus }) || n.baselineAbnormal(MetricDisk) {
+		items = append(items, MetricDisk)
+	}
+	if n.InodeNowStatus == 1 || n.InodeRateStatus == 1 || n.offsetAbnormal(func(o OffsetMetric) int { return o.InodeRateStatus }) || n.baselineAbnormal(MetricInode) {
+		items = append(items, MetricInode)
+	}
+	if n.CPUNowStatus == 1 || n.CPURateStatus == 1 || n.offsetAbnormal(func(o OffsetMetric) int { return o.CPURateStatus }) || n.baselineAbnormal(MetricCPU) {
+		items = append(items, MetricCPU)
+	}
+	if n.MemNowStatus == 1 || n.MemRateStatus == 1 || n.offsetAbnormal(func(o OffsetMetric) int { return o.MemRateStatus }) || n.baselineAbnormal(MetricMemory) {
+		items = append(items, MetricMemory)
+	}
+	if n.DiskFullStatus == 1 {
+		items = append(items, MetricForecast)
+	}
+	return items
+}
+
+// GenerateReport 根据指标数据生成报告，language为报告语言，为空时使用中文
+func GenerateReport(business string, nodes []NodeMetric, language string) (*ReportData, error) {
+	return newReportData(business, nodes, language, time.Now()), nil
+}
+
+// newReportData 按指定的生成时间构造报告数据，标题和时间按报告语言格式化
+func newReportData(business string, nodes []NodeMetric, language string, now time.Time) *ReportData {
+	catalog := i18n.NewCatalog(language)
+	return &ReportData{
+		Metadata: ReportMetadata{
+			Business:    business,
+			Title:       catalog.T("report.title", business),
+			Date:        catalog.FormatDate(now),
+			Timestamp:   catalog.FormatTimestamp(now),
+			GeneratedAt: now,
+			Language:    string(catalog.Language),
+		},
+		Inspection: InspectionData{Node: nodes},
 	}
+}
 
-	// 检查inode使用率波动
-	if node.InodeRate > 10 {
-		node.InodeRateStatus = 1
-		node.Status = 1
+// 指标阈值
+const (
+	// DiskThreshold 硬盘使用率阈值
+	DiskThreshold = 80
+	// InodeThreshold inode使用率阈值
+	InodeThreshold = 60
+	// CPUThreshold CPU使用率阈值
+	CPUThreshold = 60
+	// MemThreshold 内存使用率阈值
+	MemThreshold = 80
+	// RateThreshold 使用率波动阈值
+	RateThreshold = 10
+)
+
+// CheckThresholds 检查阈值并设置状态。
+// 有分区明细时硬盘和inode按分区及其生效的阈值检查，否则按使用率最大的分区检查。
+// comparisons为报告的对比偏移，第一个偏移的差值阈值用于主对比列，未传入时使用RateThreshold；
+// 使用率和差值按主机Thresholds中的阈值和差值规则检查，未配置规则的指标只检查使用率上升的百分点。
+func CheckThresholds(node *NodeMetric, comparisons ...Comparison) {
+	rateThreshold := float64(RateThreshold)
+	if len(comparisons) > 0 {
+		rateThreshold = comparisons[0].RateThreshold
+		checkOffsets(node, comparisons[1:])
+	}
+
+	if len(node.Filesystems) > 0 {
+		checkFilesystems(node, rateThreshold)
+	} else {
+		checkDisk(node, rateThreshold)
 	}
 
 	// 检查CPU使用率
-	if node.CPUNow > 60 {
+	if node.CPUNow > node.Thresholds.usage(MetricCPU) {
 		node.CPUNowStatus = 1
 		node.Status = 1
 	}
 
 	// 检查CPU使用率波动
-	if node.CPURate > 10 {
+	if node.deltaExceeded(MetricCPU, node.CPUOffset, node.CPURate, rateThreshold) {
 		node.CPURateStatus = 1
 		node.Status = 1
 	}
 
 	// 检查内存使用率
-	if node.MemNow > 80 {
+	if node.MemNow > node.Thresholds.usage(MetricMemory) {
 		node.MemNowStatus = 1
 		node.Status = 1
 	}
 
 	// 检查内存使用率波动
-	if node.MemRate > 10 {
+	if node.deltaExceeded(MetricMemory, node.MemOffset, node.MemRate, rateThreshold) {
 		node.MemRateStatus = 1
 		node.Status = 1
 	}
 }
+
+// checkDisk 按使用率最大的分区检查硬盘和inode使用率
+func checkDisk(node *NodeMetric, rateThreshold float64) {
+	// 检查硬盘使用率
+	if node.DiskNow > node.Thresholds.usage(MetricDisk) {
+		node.DiskNowStatus = 1
+		node.Status = 1
+	}
+
+	// 检查硬盘使用率波动
+	if node.deltaExceeded(MetricDisk, node.DiskOffset, node.DiskRate, rateThreshold) {
+		node.DiskRateStatus = 1
+		node.Status = 1
+	}
+
+	// 检查inode使用率
+	if node.InodeNow > node.Thresholds.usage(MetricInode) {
+		node.InodeNowStatus = 1
+		node.Status = 1
+	}
+
+	// 检查inode使用率波动
+	if node.deltaExceeded(MetricInode, node.InodeOffset, node.InodeRate, rateThreshold) {
+		node.InodeRateStatus = 1
+		node.Status = 1
+	}
+}
@@ -0,0 +1,445 @@
+package report
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
+)
+
+// 使用 go test ./internal/controller/report/ -args -update 重新生成黄金文件
+var update = flag.Bool("update", false, "update golden files")
+
+// expectGolden 将渲染结果与testdata下的黄金文件比较
+func expectGolden(name string, actual string) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		Expect(os.WriteFile(path, []byte(actual), 0o644)).To(Succeed())
+	}
+
+	expected, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(actual).To(Equal(string(expected)))
+}
+
+// sampleReportData 返回固定内容的报告数据，保证渲染结果稳定
+func sampleReportData() *ReportData {
+	return sampleReportDataIn(string(i18n.Chinese))
+}
+
+// sampleReportDataIn 返回指定语言的固定报告数据
+func sampleReportDataIn(language string) *ReportData {
+	nodes := []NodeMetric{
+		{
+			Name:    "192.168.0.1:9100",
+			DiskNow: 45.5, DiskOffset: 44.1, DiskRate: 1.4,
+			InodeNow: 12.3, InodeOffset: 12.3, InodeRate: 0,
+			CPUNow: 23.45, CPUOffset: 20.1, CPURate: 3.35,
+			MemNow: 61.2, MemOffset: 60.8, MemRate: 0.4,
+		},
+		{
+			Name:    "192.168.0.2:9100",
+			DiskNow: 91.2, DiskOffset: 78.6, DiskRate: 12.6,
+			InodeNow: 30, InodeOffset: 29.5, InodeRate: 0.5,
+			CPUNow: 72.8, CPUOffset: 40.3, CPURate: 32.5,
+			MemNow: 85.1, MemOffset: 84.9, MemRate: 0.2,
+		},
+	}
+	for i := range nodes {
+		CheckThresholds(&nodes[i])
+	}
+
+	generatedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	return newReportData("devops", nodes, language, generatedAt)
+}
+
+var _ = Describe("Generator", func() {
+	var generator *Generator
+
+	BeforeEach(func() {
+		generator = NewGenerator()
+	})
+
+	Context("When rendering the HTML report", func() {
+		DescribeTable("should match the golden file",
+			func(language, golden string) {
+				html, err := generator.GenerateHTML(sampleReportDataIn(language))
+				Expect(err).NotTo(HaveOccurred())
+				expectGolden(golden, html)
+			},
+			Entry("zh-CN", "zh-CN", "report.golden.html"),
+			Entry("en-US", "en-US", "report.en.golden.html"),
+		)
+
+		It("should not depend on style blocks or external resources", func() {
+			html, err := generator.GenerateHTML(sampleReportData())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(html).NotTo(ContainSubstring("<style"))
+			Expect(html).NotTo(ContainSubstring("<link"))
+			Expect(html).NotTo(ContainSubstring("class="))
+			Expect(html).NotTo(ContainSubstring("http://"))
+			Expect(html).NotTo(ContainSubstring("https://"))
+		})
+	})
+
+	Context("When rendering the summary", func() {
+		DescribeTable("should match the golden file",
+			func(language, golden string) {
+				html, err := generator.GenerateSummaryHTML(sampleReportDataIn(language))
+				Expect(err).NotTo(HaveOccurred())
+				expectGolden(golden, html)
+			},
+			Entry("zh-CN", "zh-CN", "summary.golden.html"),
+			Entry("en-US", "en-US", "summary.en.golden.html"),
+		)
+	})
+})
+
+var _ = Describe("Renderers", func() {
+	var generator *Generator
+
+	BeforeEach(func() {
+		generator = NewGenerator()
+	})
+
+	DescribeTable("should match the golden file",
+		func(format Format, language, golden string) {
+			output, err := generator.Render(format, sampleReportDataIn(language))
+			Expect(err).NotTo(HaveOccurred())
+			expectGolden(golden, string(output))
+		},
+		Entry("markdown", FormatMarkdown, "zh-CN", "report.golden.md"),
+		Entry("text", FormatText, "zh-CN", "report.golden.txt"),
+		Entry("json", FormatJSON, "zh-CN", "report.golden.json"),
+		Entry("markdown in English", FormatMarkdown, "en-US", "report.en.golden.md"),
+		Entry("text in English", FormatText, "en-US", "report.en.golden.txt"),
+		Entry("json in English", FormatJSON, "en-US", "report.en.golden.json"),
+	)
+
+	It("should localize the CSV header", func() {
+		output, err := generator.Render(FormatCSV, sampleReportDataIn("en-US"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(output)).To(HavePrefix("\ufeffHost,Disk usage(%),"))
+		Expect(string(output)).To(ContainSubstring(",Abnormal,OK,\n"))
+	})
+
+	It("should register all builtin formats", func() {
+		Expect(generator.Formats()).To(ConsistOf(
+			FormatCSV, FormatHTML, FormatJSON, FormatMarkdown, FormatText, FormatXLSX,
+		))
+	})
+
+	It("should reject unknown formats", func() {
+		_, err := generator.Render(Format("pdf"), sampleReportData())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should keep the JSON schema version", func() {
+		output, err := generator.Render(FormatJSON, sampleReportData())
+		Expect(err).NotTo(HaveOccurred())
+
+		var result map[string]interface{}
+		Expect(json.Unmarshal(output, &result)).To(Succeed())
+		Expect(result).To(HaveKeyWithValue("schemaVersion", SchemaVersion))
+	})
+
+	It("should render Prometheus query warnings", func() {
+		data := sampleReportData()
+		data.QueryWarnings = []string{"store 10.0.0.9:10901 unavailable"}
+
+		html, err := generator.GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("Prometheus查询警告（1条）"))
+		Expect(html).To(ContainSubstring("store 10.0.0.9:10901 unavailable"))
+
+		for _, format := range []Format{FormatMarkdown, FormatText} {
+			output, err := generator.Render(format, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("store 10.0.0.9:10901 unavailable"), string(format))
+		}
+
+		output, err := generator.Render(FormatJSON, data)
+		Expect(err).NotTo(HaveOccurred())
+		var report JSONReport
+		Expect(json.Unmarshal(output, &report)).To(Succeed())
+		Expect(report.Warnings).To(Equal(data.QueryWarnings))
+	})
+
+	It("should render the data source of each node", func() {
+		data := sampleReportData()
+		data.Inspection.Node[0].DataSource = "prometheus-east"
+
+		html, err := generator.GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("数据源: prometheus-east"))
+
+		text, err := generator.Render(FormatText, data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(text)).To(ContainSubstring("数据源: prometheus-east"))
+
+		output, err := generator.Render(FormatJSON, data)
+		Expect(err).NotTo(HaveOccurred())
+		var report JSONReport
+		Expect(json.Unmarshal(output, &report)).To(Succeed())
+		Expect(report.Nodes[0].DataSource).To(Equal("prometheus-east"))
+		Expect(report.Nodes[1].DataSource).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Custom templates", func() {
+	It("should render with the template funcs", func() {
+		tmpl, err := ParseTemplate("custom", `{{ range .Inspection.Node }}{{ .Name }} {{ number .DiskNow 1 }} {{ percent .CPUNow }} {{ severity .Status }};{{ end }}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		html, err := NewGenerator(WithTemplate(tmpl)).GenerateHTML(sampleReportData())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(Equal("192.168.0.1:9100 45.5 23.45% 正常;192.168.0.2:9100 91.2 72.8% 异常;"))
+	})
+
+	It("should reject templates referencing unknown fields", func() {
+		_, err := ParseTemplate("custom", `{{ range .Inspection.Node }}{{ .Hostname }}{{ end }}`)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should validate every optional section of the report", func() {
+		_, err := ParseTemplate("custom", `{{ range .Inspection.Node }}{{ range .Filesystems }}{{ .Hostname }}{{ end }}{{ end }}`)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseTemplate("custom", `{{ with .Changes }}{{ range .New }}{{ .Hostname }}{{ end }}{{ end }}`)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseTemplate("custom", `{{ range .UnreachableNodes }}{{ .Health.Hostname }}{{ end }}`)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseTemplate("custom", `{{ range .Inspection.Node }}{{ range .Offsets }}{{ .Hostname }}{{ end }}{{ end }}`)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseTemplate("custom", `{{ range .Inspection.Node }}{{ range $metric, $points := .Trends }}{{ range $points }}{{ .Hostname }}{{ end }}{{ end }}{{ end }}`)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseTemplate("custom", `{{ range .ExpiredSilences }}{{ .Hostname }}{{ end }}`)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only reparse when the template source or resourceVersion changes", func() {
+		cache := NewTemplateCache()
+
+		first, err := cache.Get("default/daily", "default/templates/report", "1", `{{ .Metadata.Title }}`)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := cache.Get("default/daily", "default/templates/report", "1", `{{ .Metadata.Business }}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+
+		third, err := cache.Get("default/daily", "default/templates/report", "2", `{{ .Metadata.Business }}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(third).NotTo(BeIdenticalTo(first))
+
+		fourth, err := cache.Get("default/daily", "default/other/report", "2", `{{ .Metadata.Business }}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fourth).NotTo(BeIdenticalTo(third))
+		Expect(cache.entries).To(HaveLen(1))
+
+		cache.Delete("default/daily")
+		Expect(cache.entries).To(BeEmpty())
+	})
+
+	It("should format durations", func() {
+		zh := i18n.NewCatalog("zh-CN")
+		Expect(formatDuration(zh, 90*time.Minute)).To(Equal("1小时30分钟"))
+		Expect(formatDuration(zh, float64(3*24*3600+7200))).To(Equal("3天2小时"))
+		Expect(formatDuration(zh, 45)).To(Equal("45秒"))
+
+		en := i18n.NewCatalog("en-US")
+		Expect(formatDuration(en, 90*time.Minute)).To(Equal("1h 30m"))
+	})
+
+	It("should fall back to the default language", func() {
+		catalog := i18n.NewCatalog("fr-FR")
+		Expect(catalog.Language).To(Equal(i18n.Chinese))
+		Expect(catalog.T("status.abnormal")).To(Equal("异常"))
+		Expect(catalog.T("no.such.key")).To(Equal("no.such.key"))
+	})
+})
+
+var _ = Describe("Forecast", func() {
+	It("should flag disks filling faster than the threshold", func() {
+		node := NodeMetric{DiskNow: 50, DiskForecast: &DiskForecast{Mountpoint: "/data", Days: 3.5}}
+		CheckThresholds(&node)
+		Expect(node.Status).To(Equal(0))
+
+		CheckForecast(&node, ForecastThreshold)
+		Expect(node.DiskFullStatus).To(Equal(1))
+		Expect(node.Status).To(Equal(1))
+		Expect(node.AbnormalItems()).To(Equal([]string{MetricForecast}))
+	})
+
+	It("should ignore disks without a growth trend", func() {
+		node := NodeMetric{DiskNow: 50}
+		CheckForecast(&node, ForecastThreshold)
+		Expect(node.Status).To(Equal(0))
+	})
+
+	It("should render the days until full column", func() {
+		data := sampleReportData()
+		data.Inspection.Forecast = &ForecastConfig{Window: "24h", ThresholdDays: ForecastThreshold}
+		data.Inspection.Node[0].DiskForecast = &DiskForecast{Mountpoint: "/data", Days: 4.26}
+		CheckForecast(&data.Inspection.Node[0], ForecastThreshold)
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("预计写满"))
+		Expect(html).To(ContainSubstring("4.3天 (/data)"))
+
+		output, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var result JSONReport
+		Expect(json.Unmarshal(output, &result)).To(Succeed())
+		Expect(result.Metadata.Forecast).To(Equal(&JSONForecastConfig{Window: "24h", ThresholdDays: 7}))
+		Expect(result.Nodes[0].Forecast).To(Equal(&JSONForecast{Mountpoint: "/data", DaysUntilFull: 4.26, Status: "abnormal"}))
+		Expect(result.Nodes[1].Forecast).To(BeNil())
+	})
+})
+
+var _ = Describe("Filesystems", func() {
+	newNode := func() NodeMetric {
+		return NodeMetric{
+			Name: "192.168.0.3:9100",
+			Filesystems: []FilesystemMetric{
+				{Device: "/dev/sda1", Mountpoint: "/", FSType: "ext4", DiskNow: 50, DiskOffset: 49, DiskRate: 1, InodeNow: 10, InodeOffset: 10},
+				{Device: "/dev/sdb1", Mountpoint: "/data", FSType: "xfs", DiskNow: 88, DiskOffset: 87, DiskRate: 1, InodeNow: 5, InodeOffset: 5},
+			},
+			DiskNow: 88, DiskOffset: 87, DiskRate: 1, InodeNow: 10, InodeOffset: 10,
+		}
+	}
+
+	It("should evaluate the default thresholds per mountpoint", func() {
+		node := newNode()
+		Expect(ApplyFilesystemThresholds(node.Filesystems, nil)).To(Succeed())
+		CheckThresholds(&node)
+
+		Expect(node.Status).To(Equal(1))
+		Expect(node.DiskNowStatus).To(Equal(1))
+		Expect(node.Filesystems[0].Status).To(Equal(0))
+		Expect(node.Filesystems[1].Status).To(Equal(1))
+	})
+
+	It("should apply the first matching mountpoint threshold", func() {
+		node := newNode()
+		Expect(ApplyFilesystemThresholds(node.Filesystems, []FilesystemThreshold{
+			{Mountpoint: "/data.*", Disk: 90},
+			{Mountpoint: "/data", Disk: 50},
+		})).To(Succeed())
+		CheckThresholds(&node)
+
+		Expect(node.Filesystems[1].DiskThreshold).To(Equal(90.0))
+		Expect(node.Filesystems[1].InodeThreshold).To(Equal(float64(InodeThreshold)))
+		Expect(node.Status).To(Equal(0))
+	})
+
+	It("should check the deltas of extra comparison offsets per filesystem", func() {
+		node := newNode()
+		// 使用率最大的/data分区只增长了8个百分点，按主机汇总值计算的差值不超过阈值；
+		// 根分区增长了12个百分点，汇总值无法体现，只有按分区检查才能发现
+		node.Offsets = []OffsetMetric{{
+			Offset: "7d", Valid: true,
+			DiskOffset: 80, DiskRate: 8, InodeOffset: 10, CPUOffset: node.CPUNow, MemOffset: node.MemNow,
+			Filesystems: []FilesystemOffset{
+				{Device: "/dev/sda1", Mountpoint: "/", DiskOffset: 38, DiskRate: 12, InodeOffset: 10},
+				{Device: "/dev/sdb1", Mountpoint: "/data", DiskOffset: 80, DiskRate: 8, InodeOffset: 5},
+			},
+		}}
+		Expect(ApplyFilesystemThresholds(node.Filesystems, []FilesystemThreshold{{Mountpoint: "/data", Disk: 90}})).To(Succeed())
+		CheckThresholds(&node, DefaultComparison, Comparison{Offset: "7d", RateThreshold: 10})
+
+		offset := node.Offsets[0]
+		Expect(offset.DiskRateStatus).To(Equal(1))
+		Expect(offset.Filesystems[0].DiskRateStatus).To(Equal(1))
+		Expect(offset.Filesystems[1].DiskRateStatus).To(Equal(0))
+		Expect(node.Status).To(Equal(1))
+	})
+
+	It("should reject invalid mountpoint patterns", func() {
+		node := newNode()
+		Expect(ApplyFilesystemThresholds(node.Filesystems, []FilesystemThreshold{{Mountpoint: "("}})).NotTo(Succeed())
+	})
+
+	It("should render the filesystem breakdown", func() {
+		data := sampleReportData()
+		node := newNode()
+		Expect(ApplyFilesystemThresholds(node.Filesystems, nil)).To(Succeed())
+		CheckThresholds(&node)
+		data.Inspection.Node = append(data.Inspection.Node, node)
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).NotTo(ContainSubstring("<details>"))
+		Expect(html).To(ContainSubstring("分区明细（2个）"))
+		Expect(html).To(ContainSubstring(`colspan="14"`))
+
+		output, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var result JSONReport
+		Expect(json.Unmarshal(output, &result)).To(Succeed())
+		Expect(result.Nodes[0].Filesystems).To(BeNil())
+		Expect(result.Nodes[2].Filesystems).To(HaveLen(2))
+		Expect(result.Nodes[2].Filesystems[1]).To(HaveField("Mountpoint", "/data"))
+		Expect(result.Nodes[2].Filesystems[1]).To(HaveField("Status", "abnormal"))
+	})
+})
+
+var _ = Describe("Unreachable nodes", func() {
+	var data *ReportData
+
+	BeforeEach(func() {
+		data = sampleReportData()
+		data.Inspection.Node = append(data.Inspection.Node,
+			NewUnreachableNode("192.168.0.9:9100", NodeHealth{State: NodeStateDown, ScrapeAge: 30 * time.Second, Reason: "up=0"}),
+			NewUnreachableNode("192.168.0.10:9100", NodeHealth{State: NodeStateUnknown, Reason: "查询CPU使用率失败"}),
+		)
+	})
+
+	It("should count unreachable nodes as abnormal", func() {
+		Expect(data.UnreachableNodes()).To(HaveLen(2))
+		Expect(data.AbnormalNodes()).To(HaveLen(3))
+		Expect(data.Inspection.Node[2].AbnormalItems()).To(Equal([]string{MetricReachability}))
+		Expect(data.Inspection.Node[2].Critical()).To(BeTrue())
+		Expect(data.Inspection.Node[3].Critical()).To(BeFalse())
+	})
+
+	It("should list unreachable nodes at the top of the HTML report", func() {
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+
+		section := strings.Index(html, "不可达主机（2台）")
+		Expect(section).To(BeNumerically(">", 0))
+		Expect(section).To(BeNumerically("<", strings.Index(html, "巡检说明")))
+		Expect(html).To(ContainSubstring("宕机"))
+		Expect(html).To(ContainSubstring("30秒"))
+		Expect(html).To(ContainSubstring(`colspan="12">查询CPU使用率失败</td>`))
+	})
+
+	It("should render unreachable rows in Markdown, text and JSON", func() {
+		markdown, err := NewGenerator().Render(FormatMarkdown, data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(markdown)).To(ContainSubstring("| 192.168.0.9:9100 | - | - | - | - | **宕机** |"))
+		Expect(string(markdown)).To(ContainSubstring("**不可达主机（2台）**: 192.168.0.9:9100 (宕机), 192.168.0.10:9100 (未知)"))
+
+		text, err := NewGenerator().Render(FormatText, data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(text)).To(ContainSubstring("[未知] 192.168.0.10:9100\n  原因: 查询CPU使用率失败\n"))
+
+		output, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var result JSONReport
+		Expect(json.Unmarshal(output, &result)).To(Succeed())
+		Expect(result.Summary.Unreachable).To(Equal(2))
+		Expect(result.Summary.Abnormal).To(Equal(1))
+		Expect(result.Nodes[2].Metrics).To(Equal(JSONMetrics{}))
+		Expect(result.Nodes[1].Metrics.Disk).NotTo(BeNil())
+		Expect(result.Nodes[2]).To(HaveField("State", NodeStateDown))
+		Expect(result.Nodes[2]).To(HaveField("ScrapeAgeSeconds", 30.0))
+		Expect(result.Nodes[0]).To(HaveField("State", NodeStateOK))
+	})
+})
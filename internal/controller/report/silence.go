@@ -0,0 +1,133 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// StatusAcknowledged 主机的异常已全部被静默规则确认，不再计入异常主机
+const StatusAcknowledged = 2
+
+// Silence 静默规则，匹配的主机异常在报告中标记为已确认
+type Silence struct {
+	// 主机名称正则表达式，需完整匹配，为空时不按名称匹配
+	Node string
+	// Prometheus目标标签，主机带有全部标签时匹配，为空时不按标签匹配
+	Selector map[string]string
+	// 指标名称，除Metric*外还可以为MetricForecast或MetricReachability
+	Metric string
+	// 静默原因和负责人
+	Reason string
+	Owner  string
+	// 过期时间，过期后不再生效并在报告中提示
+	ExpiresAt time.Time
+}
+
+// Acknowledgement 主机被静默规则确认的异常
+type Acknowledgement struct {
+	Metric  string
+	Silence Silence
+}
+
+// Expired 静默规则在now时是否已过期
+func (s Silence) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// Target 返回规则匹配的主机的描述，如 10.0.0.1:9100 {role="archive"}
+func (s Silence) Target() string {
+	target := s.Node
+	if len(s.Selector) > 0 {
+		if target != "" {
+			target += " "
+		}
+		target += formatSelector(s.Selector)
+	}
+	return target
+}
+
+// silenceMatcher 预编译主机名称正则表达式的静默规则
+type silenceMatcher struct {
+	Silence
+	node *regexp.Regexp
+}
+
+// matches 主机的某项异常是否匹配该规则
+func (m silenceMatcher) matches(node NodeMetric, metric string) bool {
+	if m.Metric != metric {
+		return false
+	}
+	if m.node != nil && !m.node.MatchString(node.Name) {
+		return false
+	}
+	return matchLabels(m.Selector, node.Labels)
+}
+
+// ApplySilences 按未过期的静默规则将主机的异常标记为已确认，全部异常均已确认的主机状态改为StatusAcknowledged。
+// 已过期的规则按过期时间排序记录到ExpiredSilences中，提醒负责人续期或删除。
+func ApplySilences(data *ReportData, silences []Silence, now time.Time) error {
+	data.ExpiredSilences = nil
+	active := make([]silenceMatcher, 0, len(silences))
+	for _, silence := range silences {
+		if silence.Node == "" && len(silence.Selector) == 0 {
+			return fmt.Errorf("静默规则（%s）必须指定node或selector", silence.Reason)
+		}
+		if silence.Expired(now) {
+			data.ExpiredSilences = append(data.ExpiredSilences, silence)
+			continue
+		}
+
+		matcher := silenceMatcher{Silence: silence}
+		if silence.Node != "" {
+			pattern, err := regexp.Compile("^(?:" + silence.Node + ")$")
+			if err != nil {
+				return fmt.Errorf("静默规则的主机%q无效: %w", silence.Node, err)
+			}
+			matcher.node = pattern
+		}
+		active = append(active, matcher)
+	}
+	sort.SliceStable(data.ExpiredSilences, func(i, j int) bool {
+		return data.ExpiredSilences[i].ExpiresAt.Before(data.ExpiredSilences[j].ExpiresAt)
+	})
+
+	for i := range data.Inspection.Node {
+		node := &data.Inspection.Node[i]
+		node.Acknowledged = nil
+		if node.Status != 1 {
+			continue
+		}
+
+		items := node.AbnormalItems()
+		for _, metric := range items {
+			for _, matcher := range active {
+				if matcher.matches(*node, metric) {
+					node.Acknowledged = append(node.Acknowledged, Acknowledgement{Metric: metric, Silence: matcher.Silence})
+					break
+				}
+			}
+		}
+		if len(items) > 0 && len(node.Acknowledged) == len(items) {
+			node.Status = StatusAcknowledged
+		}
+	}
+	return nil
+}
+
+// AcknowledgedNodes 返回异常已全部被确认的主机
+func (d *ReportData) AcknowledgedNodes() []NodeMetric {
+	var nodes []NodeMetric
+	for _, node := range d.Inspection.Node {
+		if node.Status == StatusAcknowledged {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// HasAnomalies 是否存在未被确认的异常，用于按异常发送通知时的判断
+func (d *ReportData) HasAnomalies() bool {
+	return len(d.AbnormalNodes()) > 0
+}
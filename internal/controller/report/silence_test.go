@@ -0,0 +1,109 @@
+package report
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Silences", func() {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	archive := Silence{
+		Node:      `192\.168\.0\.2:9100`,
+		Metric:    MetricDisk,
+		Reason:    "归档盘",
+		Owner:     "ops",
+		ExpiresAt: now.Add(30 * 24 * time.Hour),
+	}
+
+	// sampleSilenceData 返回第二台主机只有硬盘使用率异常的报告
+	sampleSilenceData := func() *ReportData {
+		data := sampleReportData()
+		node := &data.Inspection.Node[1]
+		*node = NodeMetric{Name: "192.168.0.2:9100", DiskNow: 95, DiskOffset: 95, Labels: map[string]string{"role": "archive"}}
+		CheckThresholds(node)
+		return data
+	}
+
+	It("should acknowledge matching findings and keep the node out of the abnormal list", func() {
+		data := sampleSilenceData()
+		Expect(ApplySilences(data, []Silence{archive}, now)).To(Succeed())
+
+		node := data.Inspection.Node[1]
+		Expect(node.Status).To(Equal(StatusAcknowledged))
+		Expect(node.Acknowledged).To(Equal([]Acknowledgement{{Metric: MetricDisk, Silence: archive}}))
+		Expect(data.AcknowledgedNodes()).To(HaveLen(1))
+		for _, abnormal := range data.AbnormalNodes() {
+			Expect(abnormal.Name).NotTo(Equal(node.Name))
+		}
+	})
+
+	It("should keep the node abnormal while other findings are not acknowledged", func() {
+		data := sampleSilenceData()
+		node := &data.Inspection.Node[1]
+		node.CPUNow = 90
+		CheckThresholds(node)
+		Expect(ApplySilences(data, []Silence{archive}, now)).To(Succeed())
+
+		Expect(node.Status).To(Equal(1))
+		Expect(node.Acknowledged).To(HaveLen(1))
+	})
+
+	It("should match nodes by target labels", func() {
+		data := sampleSilenceData()
+		silence := archive
+		silence.Node = ""
+		silence.Selector = map[string]string{"role": "archive"}
+		Expect(ApplySilences(data, []Silence{silence}, now)).To(Succeed())
+		Expect(data.Inspection.Node[1].Status).To(Equal(StatusAcknowledged))
+	})
+
+	It("should ignore expired silences and report them", func() {
+		data := sampleSilenceData()
+		expired := archive
+		expired.ExpiresAt = now.Add(-time.Hour)
+		Expect(ApplySilences(data, []Silence{expired}, now)).To(Succeed())
+
+		Expect(data.Inspection.Node[1].Status).To(Equal(1))
+		Expect(data.ExpiredSilences).To(Equal([]Silence{expired}))
+		Expect(data.HasAnomalies()).To(BeTrue())
+	})
+
+	It("should reject silences without a node matcher", func() {
+		data := sampleSilenceData()
+		Expect(ApplySilences(data, []Silence{{Metric: MetricDisk, ExpiresAt: now.Add(time.Hour)}}, now)).NotTo(Succeed())
+		Expect(ApplySilences(data, []Silence{{Node: "(", Metric: MetricDisk, ExpiresAt: now.Add(time.Hour)}}, now)).NotTo(Succeed())
+	})
+
+	It("should render acknowledged findings and expired silences", func() {
+		data := sampleSilenceData()
+		expired := Silence{Node: "192.168.0.9:9100", Metric: MetricCPU, Reason: "压测", Owner: "qa", ExpiresAt: now.Add(-time.Hour)}
+		Expect(ApplySilences(data, []Silence{archive, expired}, now)).To(Succeed())
+
+		html, err := NewGenerator().GenerateHTML(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("硬盘使用率 已确认: 归档盘（ops，至 "))
+		Expect(html).To(ContainSubstring("已过期的静默规则（1条）"))
+		Expect(html).To(ContainSubstring("压测"))
+
+		text, err := NewGenerator().generateText(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(text)).To(ContainSubstring("[已确认] 192.168.0.2:9100"))
+
+		raw, err := GenerateJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		var report JSONReport
+		Expect(json.Unmarshal(raw, &report)).To(Succeed())
+		Expect(report.Summary.Acknowledged).To(Equal(1))
+		Expect(report.Summary.Abnormal).To(Equal(1))
+		Expect(report.Nodes[1].Status).To(Equal("abnormal"))
+		Expect(report.Nodes[1].FullyAcknowledged).To(BeTrue())
+		Expect(report.Nodes[1].Acknowledged).To(Equal([]JSONAcknowledgement{
+			{Metric: MetricDisk, Reason: "归档盘", Owner: "ops", ExpiresAt: archive.ExpiresAt.Format(time.RFC3339)},
+		}))
+		Expect(report.ExpiredSilences).To(HaveLen(1))
+		Expect(report.ExpiredSilences[0].Metric).To(Equal(MetricCPU))
+	})
+})
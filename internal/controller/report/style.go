@@ -0,0 +1,134 @@
+package report
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
+)
+
+// defaultStyles 报告使用的内联样式表。
+// Outlook和部分内网邮件客户端会剥离<style>块且无法访问外部CSS，
+// 因此所有样式在生成报告时按名称合并后直接写入元素的style属性。
+var defaultStyles = map[string]string{
+	"body":           "margin: 0; padding: 0; background-color: #f8f9fa; font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; color: #333333; line-height: 1.6;",
+	"wrapper":        "width: 100%; background-color: #f8f9fa;",
+	"outer":          "padding: 20px 10px;",
+	"summary":        "padding: 16px;",
+	"container":      "width: 100%; max-width: 1200px; background-color: #ffffff; border: 1px solid #e7e7e7;",
+	"header":         "padding: 20px; background-color: #0d6efd; color: #ffffff; text-align: center;",
+	"title":          "margin: 0 0 5px; font-size: 22px; font-weight: 600; color: #ffffff;",
+	"subtitle":       "margin: 0; font-size: 14px; color: #e7f0ff;",
+	"section-wrap":   "padding: 20px 20px 0;",
+	"section":        "padding: 20px; border: 1px solid #e7e7e7; background-color: #fdfdfd;",
+	"section-title":  "margin: 0 0 16px; padding-bottom: 8px; border-bottom: 2px solid #0d6efd; font-size: 18px; font-weight: 500; color: #0a58ca;",
+	"info":           "padding: 4px 0 4px 12px; border-left: 4px solid #0d6efd; background-color: #f8f9fa; font-size: 14px;",
+	"definition":     "margin: 12px 0 8px; font-weight: 600; color: #0a58ca;",
+	"paragraph":      "margin: 0 0 8px;",
+	"list":           "margin: 0 0 8px; padding-left: 20px;",
+	"code":           "font-family: Consolas, Menlo, monospace; font-size: 12px; color: #d63384;",
+	"table":          "width: 100%; border-collapse: collapse; font-size: 12px;",
+	"th":             "padding: 8px; border: 1px solid #dee2e6; background-color: #f1f5fd; font-weight: 600; color: #495057; text-align: center;",
+	"td":             "padding: 6px; border: 1px solid #dee2e6; text-align: center; vertical-align: middle;",
+	"badge":          "display: inline-block; padding: 4px 8px; font-size: 12px; font-weight: 500; white-space: nowrap;",
+	"default":        "background-color: #f8f9fa; color: #212529;",
+	"success":        "background-color: #28a745; color: #ffffff;",
+	"danger":         "background-color: #dc3545; color: #ffffff;",
+	"warning":        "background-color: #ffc107; color: #212529;",
+	"text-danger":    "color: #dc3545;",
+	"text-success":   "color: #28a745;",
+	"legend":         "margin: 0 0 8px; font-size: 13px;",
+	"footer":         "padding: 20px; text-align: center; font-size: 12px; color: #6c757d;",
+	"muted":          "margin: 0; font-size: 12px; color: #6c757d;",
+	"section-danger": "border: 1px solid #f5c2c7; background-color: #fff5f5;",
+	"title-danger":   "border-bottom-color: #dc3545; color: #b02a37;",
+	"text-left":      "text-align: left;",
+	"fs-detail":      "padding: 4px 6px 8px 24px; text-align: left; background-color: #fafbfc;",
+	"fs-summary":     "padding: 0 0 4px; font-size: 12px; font-weight: bold; color: #6c757d; text-align: left;",
+	"heatmap":        "margin: 0 0 12px; border-collapse: separate; border-spacing: 1px; font-size: 12px;",
+	"heat-label":     "padding: 0 8px 0 0; white-space: nowrap; text-align: right; color: #495057;",
+	"heat-cell":      "width: 12px; height: 14px; padding: 0; font-size: 1px; line-height: 1px;",
+	"change":         "margin-top: 4px;",
+	"offset":         "border-left: 2px solid #adb5bd;",
+	"override":       "margin-top: 4px; font-size: 11px; color: #6c757d; white-space: nowrap;",
+	"acknowledged":   "background-color: #6c757d; color: #ffffff;",
+}
+
+// style 合并多个样式名称对应的声明，生成style属性
+func (g *Generator) style(names ...string) template.HTMLAttr {
+	declarations := make([]string, 0, len(names))
+	for _, name := range names {
+		if declaration, ok := g.styles[name]; ok {
+			declarations = append(declarations, declaration)
+		}
+	}
+	return template.HTMLAttr(`style="` + template.HTMLEscapeString(strings.Join(declarations, " ")) + `"`)
+}
+
+// statusStyle 根据指标状态返回徽标样式名称
+func statusStyle(status int) string {
+	if status == 1 {
+		return "danger"
+	}
+	return "default"
+}
+
+// overallStyle 根据主机整体状态返回徽标样式名称
+func overallStyle(status int) string {
+	switch status {
+	case 1:
+		return "danger"
+	case StatusAcknowledged:
+		return "acknowledged"
+	}
+	return "success"
+}
+
+// heatStyle 生成热力图单元格的style属性，背景色按与阈值的接近程度决定
+func (g *Generator) heatStyle(cell HeatmapCell, threshold float64) template.HTMLAttr {
+	declaration := g.styles["heat-cell"] + " background-color: " + heatColor(cell, threshold) + ";"
+	return template.HTMLAttr(`style="` + template.HTMLEscapeString(declaration) + `"`)
+}
+
+// healthStyle 根据主机采集状态返回徽标样式名称，宕机或数据过期为严重，其余为未知
+func healthStyle(node NodeMetric) string {
+	if node.Critical() {
+		return "danger"
+	}
+	return "warning"
+}
+
+// changeStyle 根据异常变化类型返回徽标样式名称
+func changeStyle(kind string) string {
+	switch kind {
+	case ChangeNew:
+		return "danger"
+	case ChangeOngoing:
+		return "warning"
+	}
+	return "success"
+}
+
+// funcMap 返回HTML模板可用的函数，内置模板和自定义模板共用
+func (g *Generator) funcMap(catalog *i18n.Catalog) template.FuncMap {
+	funcs := template.FuncMap{
+		"style":        g.style,
+		"statusStyle":  statusStyle,
+		"overallStyle": overallStyle,
+		"heatStyle":    g.heatStyle,
+		"healthStyle":  healthStyle,
+		"changeStyle":  changeStyle,
+		// 传入主机的Thresholds时按主机生效的阈值绘制，省略时使用默认阈值
+		"sparkline": func(points []Point, metric string, thresholds ...Thresholds) (template.HTML, error) {
+			threshold := metricThreshold(metric)
+			if len(thresholds) > 0 {
+				threshold = thresholds[0].usage(metric)
+			}
+			return sparkline(points, threshold, trendName(catalog, metric))
+		},
+	}
+	for name, fn := range commonFuncs(catalog) {
+		funcs[name] = fn
+	}
+	return funcs
+}
@@ -1,315 +1,520 @@
 package report
 
-// ReportTemplate 包含HTML报告模板的内容
+// ReportTemplate 包含HTML报告模板的内容。
+// 模板只使用表格布局和由style函数生成的内联样式，不依赖<style>块或任何外部资源。
 const ReportTemplate = `<!DOCTYPE html>
 <html>
   <head>
     <meta charset="utf-8" />
     <meta http-equiv="X-UA-Compatible" content="IE=edge" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
     <title>{{ .Metadata.Title }}</title>
-    <meta name="description" content />
+  </head>
+
+  <body {{ style "body" }}>
+    <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "wrapper" }}>
+      <tr>
+        <td align="center" {{ style "outer" }}>
+          <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "container" }}>
+            <tr>
+              <td {{ style "header" }}>
+                <h2 {{ style "title" }}>{{ .Metadata.Title }}</h2>
+                <p {{ style "subtitle" }}>{{ t "report.generatedAt" .Metadata.Timestamp }}</p>
+              </td>
+            </tr>
+            {{- with .UnreachableNodes }}
+
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" "section-danger" }}>
+                      <h4 {{ style "section-title" "title-danger" }}>{{ t "section.unreachable" (len .) }}</h4>
+                      <table width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+                        <thead>
+                          <tr>
+                            <th {{ style "th" }}>{{ t "column.host" }}</th>
+                            <th {{ style "th" }}>{{ t "column.state" }}</th>
+                            <th {{ style "th" }}>{{ t "column.scrapeAge" }}</th>
+                            <th {{ style "th" }}>{{ t "column.reason" }}</th>
+                          </tr>
+                        </thead>
+                        <tbody>
+                          {{ range . }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Name }}</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (healthStyle .) }}>{{ nodeStatus . }}</span></td>
+                            <td {{ style "td" }}>{{ if .Health.ScrapeAge }}{{ duration .Health.ScrapeAge }}{{ else }}-{{ end }}</td>
+                            <td {{ style "td" "text-left" }}>{{ .Health.Reason }}</td>
+                          </tr>
+                          {{ end }}
+                        </tbody>
+                      </table>
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+            {{- end }}
+            {{- with .Changes }}
+
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" }}>
+                      <h4 {{ style "section-title" }}>{{ t "section.changes" }}</h4>
+                      <p {{ style "legend" }}>{{ t "changes.previous" (timestamp .Previous) }}</p>
+                      {{- if .Empty }}
+                      <p {{ style "paragraph" }}>{{ t "changes.none" }}</p>
+                      {{- else }}
+                      <p {{ style "paragraph" }}>{{ t "changes.count" (len .New) (len .Ongoing) (len .Resolved) }}</p>
+                      <table width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+                        <thead>
+                          <tr>
+                            <th {{ style "th" }}>{{ t "column.host" }}</th>
+                            <th {{ style "th" }}>{{ t "column.change" }}</th>
+                            <th {{ style "th" }}>{{ t "column.since" }}</th>
+                          </tr>
+                        </thead>
+                        <tbody>
+                          {{- range .New }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Node }}</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (changeStyle .Kind) }}>{{ change . }}</span></td>
+                            <td {{ style "td" }}>{{ timestamp .Since }}</td>
+                          </tr>
+                          {{- end }}
+                          {{- range .Ongoing }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Node }}</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (changeStyle .Kind) }}>{{ change . }}</span></td>
+                            <td {{ style "td" }}>{{ timestamp .Since }}</td>
+                          </tr>
+                          {{- end }}
+                          {{- range .Resolved }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Node }}</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (changeStyle .Kind) }}>{{ change . }}</span></td>
+                            <td {{ style "td" }}>{{ timestamp .Since }}</td>
+                          </tr>
+                          {{- end }}
+                        </tbody>
+                      </table>
+                      {{- end }}
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+            {{- end }}
+            {{- with .ExpiredSilences }}
+
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" }}>
+                      <h4 {{ style "section-title" }}>{{ t "section.expiredSilences" (len .) }}</h4>
+                      <p {{ style "legend" }}>{{ t "silence.expiredHint" }}</p>
+                      <table width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+                        <thead>
+                          <tr>
+                            <th {{ style "th" }}>{{ t "column.host" }}</th>
+                            <th {{ style "th" }}>{{ t "column.metric" }}</th>
+                            <th {{ style "th" }}>{{ t "column.silenceReason" }}</th>
+                            <th {{ style "th" }}>{{ t "column.owner" }}</th>
+                            <th {{ style "th" }}>{{ t "column.expiresAt" }}</th>
+                          </tr>
+                        </thead>
+                        <tbody>
+                          {{- range . }}
+                          <tr>
+                            <td {{ style "td" }}>{{ .Target }}</td>
+                            <td {{ style "td" }}>{{ metricName .Metric }}</td>
+                            <td {{ style "td" "text-left" }}>{{ .Reason }}</td>
+                            <td {{ style "td" }}>{{ .Owner }}</td>
+                            <td {{ style "td" }}>{{ timestamp .ExpiresAt }}</td>
+                          </tr>
+                          {{- end }}
+                        </tbody>
+                      </table>
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+            {{- end }}
+            {{- with .QueryWarnings }}
+
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" }}>
+                      <h4 {{ style "section-title" }}>{{ t "section.queryWarnings" (len .) }}</h4>
+                      <p {{ style "legend" }}>{{ t "queryWarnings.hint" }}</p>
+                      <ul>
+                        {{- range . }}
+                        <li {{ style "paragraph" }}>{{ . }}</li>
+                        {{- end }}
+                      </ul>
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+            {{- end }}
+
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" }}>
+                      <h4 {{ style "section-title" }}>{{ t "section.description" }}</h4>
+                      <div {{ style "info" }}>
+                        <div {{ style "definition" }}>{{ t "desc.values.title" }}</div>
+                        <p {{ style "paragraph" }}>{{ t "desc.values.body" }}</p>
+
+                        <div {{ style "definition" }}>{{ t "desc.selection.title" }}</div>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.selection.modes.label" }}</strong> {{ t "desc.selection.modes.body" }}</p>
+                        <ul {{ style "list" }}>
+                          <li><strong>{{ t "desc.selection.nodes.label" }}</strong> {{ t "desc.selection.nodes.body" }}<code {{ style "code" }}>["192.18.0.1:9100", "192.18.0.2:9100"]</code></li>
+                          <li><strong>{{ t "desc.selection.labels.label" }}</strong> {{ t "desc.selection.labels.body" }}<code {{ style "code" }}>{"business": "CRM", "env": "prod"}</code>{{ t "desc.selection.labels.suffix" }}</li>
+                          <li><strong>{{ t "desc.selection.mixed.label" }}</strong> {{ t "desc.selection.mixed.body" }}</li>
+                        </ul>
+
+                        <div {{ style "definition" }}>{{ t "desc.current.title" }}</div>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.definition" }}</strong> {{ t "desc.current.definition" }}</p>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.source" }}</strong> {{ t "desc.current.source" }}</p>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.calculation" }}</strong></p>
+                        <ul {{ style "list" }}>
+                          <li><strong>{{ t "metric.cpu" }}:</strong> {{ t "desc.cpu.formula" }}<code {{ style "code" }}>(1 - avg(irate(node_cpu_seconds_total{mode="idle"}[60m])) by (instance))*100</code></li>
+                          <li><strong>{{ t "metric.memory" }}:</strong> {{ t "desc.memory.formula" }}<code {{ style "code" }}>(1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes))*100</code></li>
+                          <li><strong>{{ t "metric.disk" }}:</strong> {{ t "desc.disk.formula" }}<code {{ style "code" }}>100 - ((node_filesystem_avail_bytes / node_filesystem_size_bytes) * 100)</code></li>
+                          <li><strong>{{ t "metric.inode" }}:</strong> {{ t "desc.inode.formula" }}<code {{ style "code" }}>100 - ((node_filesystem_files_free / node_filesystem_files)*100)</code></li>
+                        </ul>
+
+                        <div {{ style "definition" }}>{{ t "desc.offset.title" .ComparisonOffset }}</div>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.definition" }}</strong> {{ t "desc.offset.definition" .ComparisonOffset }}</p>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.source" }}</strong> {{ t "desc.offset.source" .ComparisonOffset }}</p>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.metric" }}</strong> {{ t "desc.offset.metric" }}</p>
+
+                        <div {{ style "definition" }}>{{ t "desc.delta.title" }}</div>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.formula" }}</strong> {{ t "desc.delta.formula" .ComparisonOffset }}</p>
+                        <p {{ style "paragraph" }}><strong>{{ t "desc.label.meaning" }}</strong> {{ t "desc.delta.meaning" .ComparisonOffset }}</p>
+                        {{- with .ExtraComparisons }}
+                        <p {{ style "paragraph" }}>{{ t "desc.offset.extra" (offsets .) }}</p>
+                        {{- end }}
+                      </div>
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" }}>
+                      <h4 {{ style "section-title" }}>{{ t "section.result" }}</h4>
+                      <p {{ style "legend" }}>
+                        <span {{ style "badge" "success" }}>{{ t "status.normal" }}</span> {{ t "legend.normal" }}
+                      </p>
+                      <p {{ style "legend" }}>
+                        <span {{ style "badge" "danger" }}>{{ t "status.abnormal" }}</span> {{ t "legend.abnormal" }}
+                      </p>
+                      {{- with .Inspection.Forecast }}
+                      <p {{ style "legend" }}>
+                        <span {{ style "badge" "danger" }}>{{ t "status.abnormal" }}</span> {{ t "legend.forecast" .Window (number .ThresholdDays 0) }}
+                      </p>
+                      {{- end }}
+                      {{- range .Inspection.Thresholds.DeltaRules }}
+                      <p {{ style "legend" }}>
+                        <span {{ style "badge" "danger" }}>{{ t "status.abnormal" }}</span> {{ deltaRule . $.RateThreshold }}
+                      </p>
+                      {{- end }}
+                      <table width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+                        <thead>
+                          <tr>
+                            <th {{ style "th" }}>{{ t "column.host" }}</th>
+                            <th {{ style "th" }}>{{ t "column.disk" }}</th>
+                            <th {{ style "th" }}>{{ t "column.diskOffset" $.ComparisonOffset }}</th>
+                            <th {{ style "th" }}>{{ t "column.diskRate" }}</th>
+                            {{- if $.Inspection.Forecast }}
+                            <th {{ style "th" }}>{{ t "column.diskFull" }}</th>
+                            {{- end }}
+                            <th {{ style "th" }}>{{ t "column.inode" }}</th>
+                            <th {{ style "th" }}>{{ t "column.inodeOffset" $.ComparisonOffset }}</th>
+                            <th {{ style "th" }}>{{ t "column.inodeRate" }}</th>
+                            <th {{ style "th" }}>{{ t "column.cpu" }}</th>
+                            <th {{ style "th" }}>{{ t "column.cpuOffset" $.ComparisonOffset }}</th>
+                            <th {{ style "th" }}>{{ t "column.cpuRate" }}</th>
+                            <th {{ style "th" }}>{{ t "column.memory" }}</th>
+                            <th {{ style "th" }}>{{ t "column.memOffset" $.ComparisonOffset }}</th>
+                            <th {{ style "th" }}>{{ t "column.memRate" }}</th>
+                            {{- range $.ExtraComparisons }}
+                            <th {{ style "th" "offset" }}>{{ t "offset.column" (t "metric.short.disk") .Offset }}</th>
+                            <th {{ style "th" }}>{{ t "offset.column" (t "metric.short.inode") .Offset }}</th>
+                            <th {{ style "th" }}>{{ t "offset.column" (t "metric.short.cpu") .Offset }}</th>
+                            <th {{ style "th" }}>{{ t "offset.column" (t "metric.short.mem") .Offset }}</th>
+                            {{- end }}
+                            <th {{ style "th" }}>{{ t "column.status" }}</th>
+                          </tr>
+                        </thead>
+                        <tbody>
+                          {{ range .Inspection.Node }}
+                          {{- if not .Reachable }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Name }}</span>{{ with .DataSource }}<div {{ style "override" }}>{{ t "node.dataSource" . }}</div>{{ end }}</td>
+                            <td {{ style "td" "text-left" }} colspan="{{ $.MetricColumns }}">{{ .Health.Reason }}</td>
+                            <td {{ style "td" }}><span {{ style "badge" (healthStyle .) }}>{{ nodeStatus . }}</span>{{ template "changes" . }}</td>
+                          </tr>
+                          {{- else }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Name }}</span>{{ with override .Thresholds }}<div {{ style "override" }}>{{ . }}</div>{{ end }}{{ with .DataSource }}<div {{ style "override" }}>{{ t "node.dataSource" . }}</div>{{ end }}</td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .DiskNowStatus) }}>{{ .DiskNow }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .DiskOffset }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .DiskRateStatus) }}>{{ .DiskRate }}%</span></td>
+                            {{- if $.Inspection.Forecast }}
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .DiskFullStatus) }}>{{ with .DiskForecast }}{{ days .Days }} ({{ .Mountpoint }}){{ else }}-{{ end }}</span></td>
+                            {{- end }}
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .InodeNowStatus) }}>{{ .InodeNow }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .InodeOffset }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .InodeRateStatus) }}>{{ .InodeRate }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .CPUNowStatus) }}>{{ .CPUNow }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .CPUOffset }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .CPURateStatus) }}>{{ .CPURate }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .MemNowStatus) }}>{{ .MemNow }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .MemOffset }}%</span></td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .MemRateStatus) }}>{{ .MemRate }}%</span></td>
+                            {{- range .Offsets }}
+                            <td {{ style "td" "offset" }}>{{ if .Valid }}<span {{ style "badge" (statusStyle .DiskRateStatus) }}>{{ metric .DiskOffset .DiskRate }}</span>{{ else }}-{{ end }}</td>
+                            <td {{ style "td" }}>{{ if .Valid }}<span {{ style "badge" (statusStyle .InodeRateStatus) }}>{{ metric .InodeOffset .InodeRate }}</span>{{ else }}-{{ end }}</td>
+                            <td {{ style "td" }}>{{ if .Valid }}<span {{ style "badge" (statusStyle .CPURateStatus) }}>{{ metric .CPUOffset .CPURate }}</span>{{ else }}-{{ end }}</td>
+                            <td {{ style "td" }}>{{ if .Valid }}<span {{ style "badge" (statusStyle .MemRateStatus) }}>{{ metric .MemOffset .MemRate }}</span>{{ else }}-{{ end }}</td>
+                            {{- end }}
+                            <td {{ style "td" }}><span {{ style "badge" (overallStyle .Status) }}>{{ statusText .Status }}</span>{{ template "changes" . }}</td>
+                          </tr>
+                          {{- with .Filesystems }}
+                          <tr>
+                            <td {{ style "td" "fs-detail" }} colspan="{{ $.TableColumns }}">
+                              <table width="100%" cellpadding="0" cellspacing="0" border="0">
+                                <tr>
+                                  <td {{ style "fs-summary" }}>{{ t "filesystem.summary" (len .) }}</td>
+                                </tr>
+                                <tr>
+                                  <td>
+                                    <table width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+                                      <tr>
+                                        <th {{ style "th" }}>{{ t "column.mountpoint" }}</th>
+                                        <th {{ style "th" }}>{{ t "column.device" }}</th>
+                                        <th {{ style "th" }}>{{ t "column.fstype" }}</th>
+                                        <th {{ style "th" }}>{{ t "column.disk" }}</th>
+                                        <th {{ style "th" }}>{{ t "column.diskOffset" $.ComparisonOffset }}</th>
+                                        <th {{ style "th" }}>{{ t "column.diskRate" }}</th>
+                                        <th {{ style "th" }}>{{ t "column.inode" }}</th>
+                                        <th {{ style "th" }}>{{ t "column.inodeOffset" $.ComparisonOffset }}</th>
+                                        <th {{ style "th" }}>{{ t "column.inodeRate" }}</th>
+                                        <th {{ style "th" }}>{{ t "column.thresholds" }}</th>
+                                      </tr>
+                                      {{ range . }}
+                                      <tr>
+                                        <td {{ style "td" }}>{{ .Mountpoint }}</td>
+                                        <td {{ style "td" }}>{{ .Device }}</td>
+                                        <td {{ style "td" }}>{{ .FSType }}</td>
+                                        <td {{ style "td" }}><span {{ style "badge" (statusStyle .DiskNowStatus) }}>{{ .DiskNow }}%</span></td>
+                                        <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .DiskOffset }}%</span></td>
+                                        <td {{ style "td" }}><span {{ style "badge" (statusStyle .DiskRateStatus) }}>{{ .DiskRate }}%</span></td>
+                                        <td {{ style "td" }}><span {{ style "badge" (statusStyle .InodeNowStatus) }}>{{ .InodeNow }}%</span></td>
+                                        <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .InodeOffset }}%</span></td>
+                                        <td {{ style "td" }}><span {{ style "badge" (statusStyle .InodeRateStatus) }}>{{ .InodeRate }}%</span></td>
+                                        <td {{ style "td" }}>{{ .DiskThreshold }}% / {{ .InodeThreshold }}%</td>
+                                      </tr>
+                                      {{ end }}
+                                    </table>
+                                  </td>
+                                </tr>
+                              </table>
+                            </td>
+                          </tr>
+                          {{- end }}
+                          {{- end }}
+                          {{ end }}
+                        </tbody>
+                      </table>
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+
+            {{- with .Inspection.Baseline }}
+
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" }}>
+                      <h4 {{ style "section-title" }}>{{ t "section.baseline" }}</h4>
+                      <p {{ style "legend" }}>{{ t "baseline.legend" .Days .Sigma }}</p>
+                      {{- $sigma := .Sigma }}
+                      {{- with $.BaselineRows }}
+                      <table width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+                        <thead>
+                          <tr>
+                            <th {{ style "th" }}>{{ t "column.host" }}</th>
+                            <th {{ style "th" }}>{{ t "column.metric" }}</th>
+                            <th {{ style "th" }}>{{ t "column.current" }}</th>
+                            <th {{ style "th" }}>{{ t "column.baselineMean" }}</th>
+                            <th {{ style "th" }}>{{ t "column.baselineStddev" }}</th>
+                            <th {{ style "th" }}>{{ t "column.baselineRange" }}</th>
+                            <th {{ style "th" }}>{{ t "column.deviation" }}</th>
+                          </tr>
+                        </thead>
+                        <tbody>
+                          {{- range . }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Node }}</span></td>
+                            <td {{ style "td" }}>{{ metricName .Metric }}</td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .Status) }}>{{ .Current }}%</span></td>
+                            <td {{ style "td" }}>{{ .Mean }}%</td>
+                            <td {{ style "td" }}>{{ .Stddev }}</td>
+                            <td {{ style "td" }}>{{ percent (.Lower $sigma) }} ~ {{ percent (.Upper $sigma) }}</td>
+                            <td {{ style "td" }}><span {{ style "badge" (statusStyle .Status) }}>{{ sigma .Deviation }}</span></td>
+                          </tr>
+                          {{- end }}
+                        </tbody>
+                      </table>
+                      {{- else }}
+                      <p {{ style "paragraph" }}>{{ t "baseline.none" }}</p>
+                      {{- end }}
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+            {{- end }}
+
+            {{ if .HasTrends }}
+            <tr>
+              <td {{ style "section-wrap" }}>
+                <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">
+                  <tr>
+                    <td {{ style "section" }}>
+                      <h4 {{ style "section-title" }}>{{ t "section.trends" }}</h4>
+                      <p {{ style "legend" }}>{{ t "trends.legend" }}</p>
+                      <table width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+                        <thead>
+                          <tr>
+                            <th {{ style "th" }}>{{ t "column.host" }}</th>
+                            <th {{ style "th" }}>{{ trendName "disk" }}</th>
+                            <th {{ style "th" }}>{{ trendName "inode" }}</th>
+                            <th {{ style "th" }}>{{ t "metric.cpu" }}</th>
+                            <th {{ style "th" }}>{{ t "metric.memory" }}</th>
+                          </tr>
+                        </thead>
+                        <tbody>
+                          {{ range .Inspection.Node }}
+                          <tr>
+                            <td {{ style "td" }}><span {{ style "badge" "default" }}>{{ .Name }}</span></td>
+                            <td {{ style "td" }}>{{ sparkline (.Trend "disk") "disk" .Thresholds }}</td>
+                            <td {{ style "td" }}>{{ sparkline (.Trend "inode") "inode" .Thresholds }}</td>
+                            <td {{ style "td" }}>{{ sparkline (.Trend "cpu") "cpu" .Thresholds }}</td>
+                            <td {{ style "td" }}>{{ sparkline (.Trend "memory") "memory" .Thresholds }}</td>
+                          </tr>
+                          {{ end }}
+                        </tbody>
+                      </table>
+
+                      {{ range .Heatmaps }}
+                      <div {{ style "definition" }}>{{ t "heatmap.title" (trendName .Metric) }}</div>
+                      <p {{ style "muted" }}>{{ t "heatmap.range" (timestamp .Start) (timestamp .End) }}</p>
+                      <table cellpadding="0" cellspacing="0" border="0" {{ style "heatmap" }}>
+                        {{ range .Rows }}
+                        {{ $threshold := .Threshold }}
+                        <tr>
+                          <td {{ style "heat-label" }}>{{ .Name }}</td>
+                          {{ range .Cells }}<td {{ heatStyle . $threshold }} title="{{ if .Valid }}{{ percent .Value }}{{ else }}-{{ end }}">&nbsp;</td>{{ end }}
+                        </tr>
+                        {{ end }}
+                      </table>
+                      {{ end }}
+                      <p {{ style "muted" }}>{{ t "heatmap.legend" }}</p>
+                    </td>
+                  </tr>
+                </table>
+              </td>
+            </tr>
+            {{ end }}
+
+            <tr>
+              <td {{ style "footer" }}>{{ t "report.footer" .Metadata.Date }}</td>
+            </tr>
+          </table>
+        </td>
+      </tr>
+    </table>
+  </body>
+</html>
+{{- define "changes" }}{{ range .Changes }}<div {{ style "change" }}><span {{ style "badge" (changeStyle .Kind) }}>{{ change . }}</span></div>{{ end }}{{ range .Acknowledged }}<div {{ style "change" }}><span {{ style "badge" "acknowledged" }}>{{ acknowledgement . }}</span></div>{{ end }}{{ end }}`
+
+// SummaryTemplate 包含报告摘要模板的内容，完整报告以附件发送时作为邮件正文
+const SummaryTemplate = `<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8" />
     <meta name="viewport" content="width=device-width, initial-scale=1" />
-    <link rel="stylesheet" href="https://cdn.staticfile.org/twitter-bootstrap/5.3.2/css/bootstrap.min.css">
-    <style type="text/css">
-      body {
-        font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
-        color: #333;
-        background-color: #f8f9fa;
-        line-height: 1.6;
-      }
-      .container {
-        background-color: #fff;
-        box-shadow: 0 0 15px rgba(0, 0, 0, 0.1);
-        border-radius: 8px;
-        padding: 25px;
-        margin-top: 20px;
-        margin-bottom: 20px;
-      }
-      .report-header {
-        padding: 20px;
-        background: linear-gradient(135deg, #0d6efd, #0a58ca);
-        color: white;
-        border-radius: 6px;
-        margin-bottom: 25px;
-        text-align: center;
-      }
-      .report-header h2 {
-        margin-bottom: 5px;
-        font-weight: 600;
-      }
-      .report-header p {
-        opacity: 0.9;
-        margin-bottom: 0;
-      }
-      .report-section {
-        margin-bottom: 30px;
-        border: 1px solid #e7e7e7;
-        border-radius: 6px;
-        padding: 20px;
-        background-color: #fdfdfd;
-      }
-      .section-title {
-        border-bottom: 2px solid #0d6efd;
-        padding-bottom: 10px;
-        margin-bottom: 20px;
-        color: #0a58ca;
-        font-weight: 500;
-      }
-      .table-container {
-        width: 100%;
-        margin-bottom: 20px;
-        overflow-x: auto;
-      }
-      .table-responsive {
-        /* 移除高度限制 */
-        border-radius: 6px;
-        overflow: visible;
-      }
-      .table {
-        margin-bottom: 0;
-        width: 100%;
-        border-collapse: collapse;
-        font-size: 12px; /* 减小字体大小以适应更多内容 */
-      }
-      .table thead {
-        background-color: #f1f5fd;
-        /* 移除sticky定位，提高邮件兼容性 */
-        /* position: sticky; */
-        /* top: 0; */
-        /* z-index: 10; */
-      }
-      .table thead td {
-        font-weight: 600;
-        color: #495057;
-        border-bottom: 2px solid #dee2e6;
-        padding: 8px;
-        text-align: center;
-      }
-      .table tbody td {
-        padding: 6px;
-        text-align: center;
-        vertical-align: middle;
-      }
-      .table tbody tr:nth-of-type(odd) {
-        background-color: rgba(0, 0, 0, 0.02);
-      }
-      .table tbody tr:hover {
-        background-color: rgba(13, 110, 253, 0.05);
-      }
-      .badge {
-        font-size: 0.9em;
-        padding: 6px 10px;
-        border-radius: 4px;
-        font-weight: 500;
-      }
-      .text-bg-default {
-        background-color: #f8f9fa;
-        color: #212529;
-      }
-      .text-bg-success {
-        background-color: #28a745;
-      }
-      .text-bg-danger {
-        background-color: #dc3545;
-      }
-      .info-card {
-        background-color: #f8f9fa;
-        border-radius: 6px;
-        padding: 15px;
-        margin-bottom: 20px;
-      }
-      .info-section {
-        border-left: 4px solid #0d6efd;
-        padding-left: 12px;
-        margin-bottom: 15px;
-      }
-      .status-item {
-        display: flex;
-        align-items: center;
-        gap: 8px;
-      }
-      .definition-title {
-        color: #0a58ca;
-        font-weight: 600;
-        margin-top: 12px;
-        margin-bottom: 8px;
-      }
-      .collapsible-content {
-        max-height: 0;
-        overflow: hidden;
-        transition: max-height 0.3s ease;
-      }
-      .collapsible-content.expanded {
-        max-height: 1000px;
-      }
-      .report-footer {
-        text-align: center;
-        padding-top: 20px;
-        color: #6c757d;
-        font-size: 0.9em;
-      }
-    </style>
+    <title>{{ .Metadata.Title }}</title>
   </head>
 
-  <body>
-    <div class="container">
-      <div class="report-header">
-        <h2>{{ .Metadata.Business }}业务系统巡检报告</h2>
-        <p>生成时间: {{ .Metadata.Date }} {{ .Metadata.Timestamp }}</p>
-      </div>
-      
-      <div class="report-section">
-        <h4 class="section-title">巡检说明</h4>
-        
-        <div class="info-card">
-          <div class="info-section">
-            <div class="definition-title">巡检报表取值说明</div>
-            <p>以巡检时间为参考，巡检报表中的各项指标（硬盘使用率、inode使用率、CPU使用率、内存使用率）的取值规则如下：</p>
-            
-            <div class="definition-title">节点选择机制</div>
-            <p><strong>两种方式:</strong> 系统支持通过明确的节点列表或标签自动发现进行巡检</p>
-            <ul>
-              <li><strong>节点列表:</strong> 直接指定IP地址列表，如<code>["192.18.0.1:9100", "192.18.0.2:9100"]</code></li>
-              <li><strong>标签自动发现:</strong> 通过标签自动查找匹配的节点，如<code>{"business": "CRM", "env": "prod"}</code>，系统将查询所有具有这些标签的节点</li>
-              <li><strong>混合模式:</strong> 同时支持指定节点列表和标签，将对两者找到的所有节点进行巡检</li>
-            </ul>
-            
-            <div class="definition-title">1. 当前值（最近5分钟内的最新值）</div>
-            <p><strong>定义:</strong> 当前值表示系统在最近 5 分钟内的最新状态。</p>
-            <p><strong>来源:</strong> 从 Prometheus 监控系统获取的实时查询结果。</p>
-            <p><strong>指标计算:</strong></p>
-            <ul>
-              <li><strong>CPU使用率:</strong> 计算过去60分钟内非空闲CPU时间的平均百分比，公式：<code>(1 - avg(irate(node_cpu_seconds_total{mode="idle"}[60m])) by (instance))*100</code></li>
-              <li><strong>内存使用率:</strong> 计算已使用内存占总内存的百分比，公式：<code>(1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes))*100</code></li>
-              <li><strong>硬盘使用率:</strong> 计算已使用硬盘空间使用率最大的分区，公式：<code>max(100 - ((node_filesystem_avail_bytes / node_filesystem_size_bytes) * 100))</code></li>
-              <li><strong>inode使用率:</strong> 计算已使用inode使用率最大的分区，公式：<code>max(100 - ((node_filesystem_files_free / node_filesystem_files)*100))</code></li>
-            </ul>
-            
-            <div class="definition-title">2. 24小时值（前24小时的一个值）</div>
-            <p><strong>定义:</strong> 表示系统在前 24 小时的一个采样点数据。</p>
-            <p><strong>来源:</strong> 从 Prometheus 监控系统中查询 24 小时前的历史记录，使用相同的查询方式但指定了时间偏移。</p>
-            <p><strong>指标:</strong> 与当前值使用相同的计算公式，只是时间点不同。</p>
-          
-            <div class="definition-title">3. 差值（增减率百分比）</div>
-            <p><strong>公式:</strong> 当前值% - 24小时前值% = 差值</p>
-            <p><strong>含义:</strong> 反映当前系统状态相较于前 24 小时是否发生显著变化，并用百分比表示增减幅度。</p>
-          </div>
-        </div>
-      </div>
-      
-      <div class="report-section">
-        <h4 class="section-title">服务器巡检结果</h4>
-          <div class="status-item">
-            <span class="badge text-bg-success">正常</span>
-            <span>巡检使用率正常</span>
-          </div>
-          <div class="status-item">
-            <span class="badge text-bg-danger">异常</span>
-            <span>硬盘高于80% or inode使用率高于60% or CPU平均使用率高于60% or 内存平均使用率高于80% or 波动幅度大于10%</span>
-          </div>
-        <div class="table-container">
-          <table class="table table-bordered">
-            <thead>
-              <tr>
-                <td style="width: 500px">主机IP</td>
-                <td style="width: 150px">硬盘使用率</td>
-                <td style="width: 150px">硬盘使用率前24h</td>
-                <td style="width: 150px">硬盘使用率差值</td>
-                <td style="width: 150px">inode使用率</td>
-                <td style="width: 150px">inode使用率前24h</td>
-                <td style="width: 150px">inode使用率差值</td>
-                <td style="width: 150px">CPU使用率</td>
-                <td style="width: 150px">CPU使用率前24h</td>
-                <td style="width: 150px">CPU使用率差值</td>
-                <td style="width: 150px">内存使用率</td>
-                <td style="width: 150px">内存使用率前24h</td>
-                <td style="width: 150px">内存使用率差值</td>
-                <td style="width: 100px">状态</td>
-              </tr>
-            </thead>
-            <tbody>
-              {{ range .Inspection.Node }}
-              <tr>
-                <td>
-                  <span class="badge text-bg-default">
-                    {{ .Name }}
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .DiskNowStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .DiskNow }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge text-bg-default">
-                    {{ .DiskOffset }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .DiskRateStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .DiskRate }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .InodeNowStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .InodeNow }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge text-bg-default">
-                    {{ .InodeOffset }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .InodeRateStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .InodeRate }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .CPUNowStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .CPUNow }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge text-bg-default">
-                    {{ .CPUOffset }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .CPURateStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .CPURate }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .MemNowStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .MemNow }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge text-bg-default">
-                    {{ .MemOffset }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .MemRateStatus 1 }}text-bg-danger{{ else }}text-bg-default{{ end }}">
-                    {{ .MemRate }}%
-                  </span>
-                </td>
-                <td>
-                  <span class="badge {{ if eq .Status 1 }}text-bg-danger{{ else }}text-bg-success{{ end }}">
-                    {{ if eq .Status 1 }}异常{{ else }}正常{{ end }}
-                  </span>
-                </td>
-              </tr>
-              {{ end }}
-            </tbody>
+  <body {{ style "body" }}>
+    <table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" {{ style "wrapper" }}>
+      <tr>
+        <td {{ style "summary" }}>
+          <h2 {{ style "section-title" }}>{{ .Metadata.Title }}</h2>
+          <p {{ style "muted" }}>{{ t "report.generatedAt" .Metadata.Timestamp }}</p>
+
+          {{ $abnormal := .AbnormalNodes }}
+          <p {{ style "paragraph" }}>
+            {{ if $abnormal }}
+            <strong {{ style "text-danger" }}>{{ t "summary.abnormal" (len .Inspection.Node) (len $abnormal) }}</strong>
+            {{ else }}
+            <strong {{ style "text-success" }}>{{ t "summary.allNormal" (len .Inspection.Node) }}</strong>
+            {{ end }}
+          </p>
+          {{- with .Changes }}
+          <p {{ style "paragraph" }}>{{ t "changes.count" (len .New) (len .Ongoing) (len .Resolved) }}</p>
+          {{- end }}
+          {{- with .AcknowledgedNodes }}
+          <p {{ style "paragraph" }}>{{ t "summary.acknowledged" (len .) }}</p>
+          {{- end }}
+          {{- with .ExpiredSilences }}
+          <p {{ style "paragraph" "text-danger" }}>{{ t "section.expiredSilences" (len .) }}</p>
+          {{- end }}
+          {{- with .QueryWarnings }}
+          <p {{ style "paragraph" }}>{{ t "section.queryWarnings" (len .) }}</p>
+          {{- end }}
+
+          {{ if $abnormal }}
+          <table cellpadding="0" cellspacing="0" border="0" {{ style "table" }}>
+            <tr>
+              <th {{ style "th" }}>{{ t "column.host" }}</th>
+              <th {{ style "th" }}>{{ t "summary.column.items" }}</th>
+            </tr>
+            {{ range $abnormal }}
+            <tr>
+              <td {{ style "td" }}>{{ .Name }}</td>
+              <td {{ style "td" "text-danger" }}>{{ if .Reachable }}{{ metricList .AbnormalItems }}{{ else }}{{ nodeStatus . }}: {{ .Health.Reason }}{{ end }}</td>
+            </tr>
+            {{ end }}
           </table>
-        </div>
-      </div>
-      
-      <div class="report-footer">
-        此报告由自动巡检系统生成 &copy; {{ .Metadata.Date }}
-      </div>
-    </div>
+          {{ end }}
+
+          <p {{ style "footer" }}>{{ t "summary.attached" }} {{ t "report.footer" .Metadata.Date }}</p>
+        </td>
+      </tr>
+    </table>
   </body>
 </html>`
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
+)
+
+// MarkdownTemplate 包含Markdown报告模板的内容，适合聊天机器人等场景
+const MarkdownTemplate = `## {{ .Metadata.Title }}
+
+{{ t "report.generatedAt" .Metadata.Timestamp }}
+
+{{ t "summary.count" (len .Inspection.Node) (len .AbnormalNodes) }}
+{{- with .Changes }}
+
+**{{ t "section.changes" }}**: {{ t "changes.count" (len .New) (len .Ongoing) (len .Resolved) }}
+{{- range .New }}
+- {{ md .Node }}: {{ change . }}
+{{- end }}
+{{- range .Resolved }}
+- {{ md .Node }}: {{ change . }}
+{{- end }}
+{{- end }}
+{{- with .UnreachableNodes }}
+
+**{{ t "section.unreachable" (len .) }}**: {{ range $i, $node := . }}{{ if $i }}, {{ end }}{{ md $node.Name }} ({{ nodeStatus $node }}){{ end }}
+{{- end }}
+{{- with .AcknowledgedNodes }}
+
+{{ t "summary.acknowledged" (len .) }}
+{{- end }}
+{{- with .ExpiredSilences }}
+
+**{{ t "section.expiredSilences" (len .) }}**
+{{- range . }}
+- {{ md .Target }} {{ metricName .Metric }}: {{ md .Reason }} ({{ md .Owner }}, {{ timestamp .ExpiresAt }})
+{{- end }}
+{{- end }}
+{{- with .QueryWarnings }}
+
+**{{ t "section.queryWarnings" (len .) }}**
+{{- range . }}
+- {{ md . }}
+{{- end }}
+{{- end }}
+
+| {{ t "column.host" }} | {{ t "metric.disk" }} | {{ t "metric.inode" }} | {{ t "metric.cpu" }} | {{ t "metric.memory" }} | {{ t "column.status" }} |
+| --- | --- | --- | --- | --- | --- |
+{{ range .Inspection.Node -}}
+{{ if not .Reachable -}}
+| {{ md .Name }} | - | - | - | - | **{{ nodeStatus . }}** |
+{{ else -}}
+| {{ md .Name }} | {{ mdMetric .DiskNow .DiskRate .DiskNowStatus .DiskRateStatus }} | {{ mdMetric .InodeNow .InodeRate .InodeNowStatus .InodeRateStatus }} | {{ mdMetric .CPUNow .CPURate .CPUNowStatus .CPURateStatus }} | {{ mdMetric .MemNow .MemRate .MemNowStatus .MemRateStatus }} | {{ if eq .Status 1 }}**{{ statusText .Status }}**{{ else }}{{ statusText .Status }}{{ end }} |
+{{ end -}}
+{{ end }}
+> {{ t "note.markdown" .ComparisonOffset }}
+`
+
+// TextTemplate 包含纯文本报告模板的内容
+const TextTemplate = `{{ .Metadata.Title }}
+{{ t "report.generatedAt" .Metadata.Timestamp }}
+{{ t "summary.count" (len .Inspection.Node) (len .AbnormalNodes) }}
+{{- with .Changes }}
+{{ t "section.changes" }}: {{ t "changes.count" (len .New) (len .Ongoing) (len .Resolved) }}
+{{- end }}
+{{- with .UnreachableNodes }}
+{{ t "section.unreachable" (len .) }}: {{ range $i, $node := . }}{{ if $i }}, {{ end }}{{ $node.Name }}{{ end }}
+{{- end }}
+{{- with .AcknowledgedNodes }}
+{{ t "summary.acknowledged" (len .) }}
+{{- end }}
+{{- with .ExpiredSilences }}
+{{ t "section.expiredSilences" (len .) }}:
+{{- range . }}
+  {{ .Target }} {{ metricName .Metric }}: {{ .Reason }} ({{ .Owner }}, {{ timestamp .ExpiresAt }})
+{{- end }}
+{{- end }}
+{{- with .QueryWarnings }}
+{{ t "section.queryWarnings" (len .) }}:
+{{- range . }}
+  {{ . }}
+{{- end }}
+{{- end }}
+{{ range .Inspection.Node }}
+[{{ nodeStatus . }}] {{ .Name }}
+{{- with .DataSource }}
+  {{ t "node.dataSource" . }}
+{{- end }}
+{{- if not .Reachable }}
+  {{ t "column.reason" }}: {{ .Health.Reason }}
+{{- else }}
+  {{ t "metric.short.disk" }} {{ metric .DiskNow .DiskRate }}  {{ t "metric.short.inode" }} {{ metric .InodeNow .InodeRate }}  {{ t "metric.short.cpu" }} {{ metric .CPUNow .CPURate }}  {{ t "metric.short.mem" }} {{ metric .MemNow .MemRate }}
+{{- range .Offsets }}{{ if .Valid }}
+  {{ t "offset.group" .Offset }}: {{ t "metric.short.disk" }} {{ metric .DiskOffset .DiskRate }}  {{ t "metric.short.inode" }} {{ metric .InodeOffset .InodeRate }}  {{ t "metric.short.cpu" }} {{ metric .CPUOffset .CPURate }}  {{ t "metric.short.mem" }} {{ metric .MemOffset .MemRate }}
+{{- end }}{{ end }}
+{{- with .AbnormalItems }}
+  {{ t "summary.items" (metricList .) }}
+{{- end }}
+{{- with .DiskForecast }}
+  {{ t "forecast.text" .Mountpoint (days .Days) }}
+{{- end }}
+{{- range .Filesystems }}{{ if eq .Status 1 }}
+  {{ .Mountpoint }}: {{ t "metric.short.disk" }} {{ metric .DiskNow .DiskRate }}  {{ t "metric.short.inode" }} {{ metric .InodeNow .InodeRate }}
+{{- end }}{{ end }}
+{{- with override .Thresholds }}
+  {{ . }}
+{{- end }}
+{{- end }}
+{{- range .BaselineRows }}{{ if eq .Status 1 }}
+  {{ t "baseline.text" (metricName .Metric) (percent .Current) (percent .Mean) (sigma .Deviation) }}
+{{- end }}{{ end }}
+{{- range .Acknowledged }}
+  {{ acknowledgement . }}
+{{- end }}
+{{- with .Changes }}
+  {{ t "column.change" }}: {{ range $i, $change := . }}{{ if $i }}{{ t "list.separator" }}{{ end }}{{ change $change }}{{ end }}
+{{- end }}
+{{ end }}
+{{ t "note.text" .ComparisonOffset }}
+`
+
+// markdownMetric 格式化Markdown中的指标，超过阈值时加粗
+func markdownMetric(now, rate float64, nowStatus, rateStatus int) string {
+	value := formatMetric(now, rate)
+	if nowStatus == 1 || rateStatus == 1 {
+		return "**" + value + "**"
+	}
+	return value
+}
+
+// markdownEscaper 转义Markdown表格中有特殊含义的字符
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, `*`, `\*`, `_`, `\_`, "`", "\\`")
+
+// textFuncMap 返回文本类模板可用的函数
+func textFuncMap(catalog *i18n.Catalog) template.FuncMap {
+	funcs := template.FuncMap{
+		"mdMetric": markdownMetric,
+		"md":       markdownEscaper.Replace,
+	}
+	for name, fn := range commonFuncs(catalog) {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// executeText 使用text/template渲染报告
+func executeText(name, text string, data *ReportData) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(textFuncMap(data.Catalog())).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析%s模板失败: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("执行%s模板失败: %w", name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// generateMarkdown 生成Markdown报告
+func (g *Generator) generateMarkdown(data *ReportData) ([]byte, error) {
+	return executeText("markdown", MarkdownTemplate, data)
+}
+
+// generateText 生成纯文本报告
+func (g *Generator) generateText(data *ReportData) ([]byte, error) {
+	return executeText("text", TextTemplate, data)
+}
@@ -0,0 +1,181 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/i18n"
+)
+
+// 以下为生成最小可用XLSX工作簿所需的固定部件
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	// 工作表名称按报告语言填充
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+	// 单元格样式：0默认，1表头，2两位小数，3异常的数值（红底白字），4已确认异常的数值（灰底白字）
+	// 条件格式：dxf 0为异常（红底白字），dxf 1为正常（绿底白字）
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="3"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font><font><color rgb="FFFFFFFF"/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="5"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill><fill><patternFill patternType="solid"><fgColor rgb="FFF1F5FD"/><bgColor indexed="64"/></patternFill></fill><fill><patternFill patternType="solid"><fgColor rgb="FFDC3545"/><bgColor indexed="64"/></patternFill></fill><fill><patternFill patternType="solid"><fgColor rgb="FF6C757D"/><bgColor indexed="64"/></patternFill></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="5"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/><xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="2" fontId="2" fillId="3" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1" applyFill="1"/><xf numFmtId="2" fontId="2" fillId="4" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1" applyFill="1"/></cellXfs>
+<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>
+<dxfs count="2"><dxf><font><color rgb="FFFFFFFF"/></font><fill><patternFill><bgColor rgb="FFDC3545"/></patternFill></fill></dxf><dxf><font><color rgb="FFFFFFFF"/></font><fill><patternFill><bgColor rgb="FF28A745"/></patternFill></fill></dxf></dxfs>
+</styleSheet>`
+)
+
+// xlsxValueStyle 返回数值单元格的样式，与HTML报告一致按主机计算出的状态着色，
+// 这样主机级阈值覆盖、分区阈值和变化规则都能体现在工作簿中；已被静默规则确认的异常显示为灰色
+func xlsxValueStyle(node NodeMetric, column, status int) int {
+	if status != 1 {
+		return 2
+	}
+	metric := exportMetrics[column/3]
+	for _, ack := range node.Acknowledged {
+		if ack.Metric == metric {
+			return 4
+		}
+	}
+	return 3
+}
+
+// GenerateXLSX 将主机指标导出为带条件格式的Excel工作簿
+func GenerateXLSX(data *ReportData) ([]byte, error) {
+	catalog := data.Catalog()
+
+	var sheetName bytes.Buffer
+	_ = xml.EscapeText(&sheetName, []byte(catalog.T("xlsx.sheet")))
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, sheetName.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", xlsxSheet(catalog, data.ComparisonOffset(), data.Inspection.Node)},
+	}
+
+	for _, part := range parts {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("创建XLSX部件%s失败: %w", part.name, err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("写入XLSX部件%s失败: %w", part.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("生成XLSX失败: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// xlsxSheet 生成工作表内容
+func xlsxSheet(catalog *i18n.Catalog, offset string, nodes []NodeMetric) string {
+	var buf bytes.Buffer
+	lastRow := len(nodes) + 1
+	lastCol := columnName(len(exportColumns) - 1)
+
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	fmt.Fprintf(&buf, `<dimension ref="A1:%s%d"/>`, lastCol, lastRow)
+	// 冻结表头
+	buf.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	buf.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+	buf.WriteString(`</sheetView></sheetViews>`)
+	fmt.Fprintf(&buf, `<cols><col min="1" max="1" width="24" customWidth="1"/><col min="2" max="%d" width="18" customWidth="1"/></cols>`,
+		len(exportColumns))
+
+	buf.WriteString(`<sheetData>`)
+	buf.WriteString(`<row r="1">`)
+	for i, title := range exportHeader(catalog, offset) {
+		writeStringCell(&buf, i, 1, title, 1)
+	}
+	buf.WriteString(`</row>`)
+
+	for i, node := range nodes {
+		row := i + 2
+		fmt.Fprintf(&buf, `<row r="%d">`, row)
+		writeStringCell(&buf, 0, row, node.Name, 0)
+		// 主机不可达时不写数值单元格
+		statuses := exportStatuses(node)
+		for j, v := range exportValues(node) {
+			fmt.Fprintf(&buf, `<c r="%s%d" s="%d"><v>%s</v></c>`, columnName(j+1), row,
+				xlsxValueStyle(node, j, statuses[j]), strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		for j, text := range exportStatus(catalog, node) {
+			writeStringCell(&buf, exportStatusColumn+j, row, text, 0)
+		}
+		buf.WriteString(`</row>`)
+	}
+	buf.WriteString(`</sheetData>`)
+
+	fmt.Fprintf(&buf, `<autoFilter ref="A1:%s%d"/>`, lastCol, lastRow)
+
+	if len(nodes) > 0 {
+		// 状态列按文本着色，数值列已按状态设置了单元格样式
+		statusCol := columnName(exportStatusColumn)
+		fmt.Fprintf(&buf, `<conditionalFormatting sqref="%s2:%s%d">`, statusCol, statusCol, lastRow)
+		fmt.Fprintf(&buf, `<cfRule type="cellIs" dxfId="0" priority="1" operator="equal"><formula>"%s"</formula></cfRule>`,
+			statusText(catalog, 1))
+		fmt.Fprintf(&buf, `<cfRule type="cellIs" dxfId="1" priority="2" operator="equal"><formula>"%s"</formula></cfRule>`,
+			statusText(catalog, 0))
+		buf.WriteString(`</conditionalFormatting>`)
+	}
+
+	buf.WriteString(`</worksheet>`)
+	return buf.String()
+}
+
+// writeStringCell 写入内联字符串单元格
+func writeStringCell(buf *bytes.Buffer, col, row int, value string, style int) {
+	fmt.Fprintf(buf, `<c r="%s%d" t="inlineStr" s="%d"><is><t>`, columnName(col), row, style)
+	_ = xml.EscapeText(buf, []byte(value))
+	buf.WriteString(`</t></is></c>`)
+}
+
+// columnName 将从0开始的列下标转换为Excel列名，如0为A，26为AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
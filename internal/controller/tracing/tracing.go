@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName 巡检流程使用的Tracer名称
+const tracerName = "github.com/rxg456/auto-inspection-operator"
+
+// 巡检流程中span的属性
+const (
+	AttrNamespace  = attribute.Key("autoinspection.namespace")
+	AttrName       = attribute.Key("autoinspection.name")
+	AttrJob        = attribute.Key("autoinspection.job")
+	AttrNode       = attribute.Key("autoinspection.node")
+	AttrNodeState  = attribute.Key("autoinspection.node.state")
+	AttrDataSource = attribute.Key("autoinspection.datasource")
+	AttrQuery      = attribute.Key("prometheus.query")
+	AttrQueryType  = attribute.Key("prometheus.query.type")
+	AttrChannel    = attribute.Key("notification.channel")
+	AttrWebhook    = attribute.Key("notification.webhook")
+	AttrFormat     = attribute.Key("report.format")
+)
+
+// Config 链路追踪配置
+type Config struct {
+	// OTLP gRPC接收端地址，如 otel-collector:4317，为空时不开启追踪
+	Endpoint string
+	// 使用明文连接接收端
+	Insecure bool
+	// 采样比例，0到1之间
+	SampleRatio float64
+	// 上报的服务名称
+	ServiceName string
+}
+
+// Setup 按配置初始化全局TracerProvider，返回的函数在退出前调用，用于上报剩余的span。
+// 未配置接收端地址时不开启追踪，span不会被记录
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建OTLP导出器失败: %w", err)
+	}
+
+	provider := NewProvider(sdktrace.NewBatchSpanProcessor(exporter), config)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// NewProvider 创建按比例采样的TracerProvider，测试中可传入内存导出器
+func NewProvider(processor sdktrace.SpanProcessor, config Config) *sdktrace.TracerProvider {
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = "auto-inspection-operator"
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+}
+
+// Start 使用全局TracerProvider创建span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 结束span，err不为空时记录错误并将span状态设为Error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
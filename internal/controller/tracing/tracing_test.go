@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("Tracing", func() {
+	var exporter *tracetest.InMemoryExporter
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		provider := NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), Config{SampleRatio: 1})
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(provider)
+		DeferCleanup(func() {
+			otel.SetTracerProvider(previous)
+			Expect(provider.Shutdown(context.Background())).To(Succeed())
+		})
+	})
+
+	It("should nest spans and record errors", func() {
+		ctx, parent := Start(context.Background(), "RunInspection", AttrName.String("prod"))
+		_, child := Start(ctx, "CollectNode", AttrNode.String("10.0.0.1:9100"))
+		End(child, errors.New("connection refused"))
+		End(parent, nil)
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("CollectNode"))
+		Expect(spans[0].Parent.SpanID()).To(Equal(spans[1].SpanContext.SpanID()))
+		Expect(spans[0].Status.Code).To(Equal(codes.Error))
+		Expect(spans[0].Events[0].Name).To(Equal("exception"))
+		Expect(spans[1].Status.Code).To(Equal(codes.Unset))
+		Expect(spans[1].Attributes).To(ContainElement(AttrName.String("prod")))
+		Expect(spans[1].Resource.Attributes()).To(ContainElement(HaveField("Value.AsString()", "auto-inspection-operator")))
+	})
+
+	It("should not record spans when the sample ratio is zero", func() {
+		provider := NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), Config{})
+		otel.SetTracerProvider(provider)
+
+		_, span := Start(context.Background(), "Reconcile")
+		End(span, nil)
+		Expect(exporter.GetSpans()).To(BeEmpty())
+	})
+
+	It("should be disabled without an endpoint", func() {
+		provider := otel.GetTracerProvider()
+		shutdown, err := Setup(context.Background(), Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(context.Background())).To(Succeed())
+		Expect(otel.GetTracerProvider()).To(BeIdenticalTo(provider))
+	})
+})
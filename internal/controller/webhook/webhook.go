@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rxg456/auto-inspection-operator/internal/controller/metrics"
+)
+
+// Sender Webhook发送器
+type Sender struct {
+	Client *http.Client
+}
+
+// NewSender 创建一个新的Webhook发送器
+func NewSender() *Sender {
+	return &Sender{
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Send 将报告POST到指定地址，并记录发送结果
+func (s *Sender) Send(ctx context.Context, url, contentType string, body []byte) error {
+	err := s.send(ctx, url, contentType, body)
+	metrics.ObserveNotification(metrics.ChannelWebhook, err)
+	return err
+}
+
+// send 发送Webhook请求，非2xx响应视为失败
+func (s *Sender) send(ctx context.Context, url, contentType string, body []byte) error {
+	logger := log.FromContext(ctx).WithName("webhook-sender")
+	logger.Info("准备发送Webhook", "url", url, "contentType", contentType)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建Webhook请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送Webhook失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sender", func() {
+	// request 记录假服务端收到的请求
+	type request struct {
+		method      string
+		contentType string
+		body        string
+	}
+
+	var (
+		received chan request
+		status   atomic.Int32
+		sender   *Sender
+		server   *httptest.Server
+	)
+
+	BeforeEach(func() {
+		received = make(chan request, 1)
+		status.Store(http.StatusOK)
+		sender = NewSender()
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- request{method: r.Method, contentType: r.Header.Get("Content-Type"), body: string(body)}
+			w.WriteHeader(int(status.Load()))
+			_, _ = w.Write([]byte("rejected by receiver"))
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	It("should POST the body with the content type", func() {
+		Expect(sender.Send(context.Background(), server.URL, "application/json", []byte(`{"status":"ok"}`))).To(Succeed())
+
+		var req request
+		Eventually(received).Should(Receive(&req))
+		Expect(req.method).To(Equal(http.MethodPost))
+		Expect(req.contentType).To(Equal("application/json"))
+		Expect(req.body).To(Equal(`{"status":"ok"}`))
+	})
+
+	It("should accept any 2xx response", func() {
+		status.Store(http.StatusAccepted)
+		Expect(sender.Send(context.Background(), server.URL, "text/plain; charset=UTF-8", []byte("ok"))).To(Succeed())
+	})
+
+	DescribeTable("should fail on non-2xx responses",
+		func(code int) {
+			status.Store(int32(code))
+			err := sender.Send(context.Background(), server.URL, "text/markdown; charset=UTF-8", []byte("# report"))
+			Expect(err).To(MatchError(ContainSubstring("unexpected status code: %d", code)))
+			Expect(err).To(MatchError(ContainSubstring("rejected by receiver")))
+		},
+		Entry("unauthorized", http.StatusUnauthorized),
+		Entry("client error", http.StatusBadRequest),
+		Entry("server error", http.StatusInternalServerError),
+	)
+
+	It("should stop when the context is cancelled", func() {
+		release := make(chan struct{})
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 读完请求体后服务端才能感知客户端断开连接
+			_, _ = io.Copy(io.Discard, r.Body)
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+		DeferCleanup(slow.Close)
+		DeferCleanup(func() { close(release) })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := sender.Send(ctx, slow.URL, "application/json", []byte("{}"))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("should fail on an invalid URL", func() {
+		Expect(sender.Send(context.Background(), "://invalid", "application/json", nil)).
+			To(MatchError(ContainSubstring("创建Webhook请求失败")))
+	})
+})